@@ -0,0 +1,167 @@
+package sharedjob
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type (
+	// BotRule matches jobs by type and starting station. Empty lists match everything.
+	BotRule struct {
+		JobTypes []JobType   `json:"job_types"`
+		Stations []StationID `json:"stations"`
+	}
+	BotConfig struct {
+		Name               string    `json:"name"`
+		Rules              []BotRule `json:"rules"`
+		TravelDelaySeconds int       `json:"travel_delay_seconds"`
+		PollSeconds        int       `json:"poll_seconds"`
+	}
+)
+
+func (r BotRule) Matches(j *Job) bool {
+	if len(r.JobTypes) > 0 && !slices.Contains(r.JobTypes, j.JobType) {
+		return false
+	}
+
+	if len(r.Stations) > 0 && !slices.Contains(r.Stations, j.StartingStationName) {
+		return false
+	}
+
+	return true
+}
+
+func (c BotConfig) Matches(j *Job) bool {
+	for _, rule := range c.Rules {
+		if rule.Matches(j) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func LoadBotConfigs(path string) ([]BotConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read bot config: %w", err)
+	}
+
+	configs := make([]BotConfig, 0)
+	if err := json.Unmarshal(raw, &configs); err != nil {
+		return nil, fmt.Errorf("could not parse bot config: %w", err)
+	}
+
+	for _, c := range configs {
+		if c.Name == "" {
+			return nil, fmt.Errorf("bot without name in %s", path)
+		}
+	}
+
+	return configs, nil
+}
+
+// StartBots registers every bot as player and starts a worker per bot that works
+// through matching jobs: reserve, travel, take, travel, finish. The workers stop once ctx
+// is cancelled, the returned wait blocks until all of them have.
+func StartBots(ctx context.Context, configs []BotConfig, progressCh chan<- ProgressMessage) (wait func()) {
+	wg := &sync.WaitGroup{}
+	for _, c := range configs {
+		addPlayer(&Player{
+			Username:       c.Name,
+			subbedStations: make([]StationID, 0),
+			bot:            true,
+		})
+		GrantLicenses(c.Name, AllLicenses()...)
+
+		wg.Add(1)
+		go func(c BotConfig) {
+			defer wg.Done()
+			runBot(ctx, c, progressCh)
+		}(c)
+	}
+
+	return wg.Wait
+}
+
+// botSleep waits for d and reports false if ctx got cancelled meanwhile
+func botSleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func runBot(ctx context.Context, c BotConfig, progressCh chan<- ProgressMessage) {
+	botLog := logrus.WithField("bot", c.Name)
+	travelDelay := time.Duration(c.TravelDelaySeconds) * time.Second
+	pollDelay := time.Duration(c.PollSeconds) * time.Second
+	if pollDelay <= 0 {
+		pollDelay = 10 * time.Second
+	}
+
+	// jobs the bot could not reserve or take are not tried again
+	failed := make(map[string]bool)
+
+	for botSleep(ctx, pollDelay) {
+
+		jobID := findBotJob(c, failed)
+		if jobID == "" {
+			continue
+		}
+
+		if !ReserveJob(c.Name, jobID) {
+			failed[jobID] = true
+			continue
+		}
+		botLog.WithField("job_id", jobID).Info("bot reserved job")
+
+		if !botSleep(ctx, travelDelay) {
+			// hand the job back instead of leaving it reserved by a stopped bot
+			UnreserveJob(c.Name, jobID)
+			return
+		}
+		if ok, _, _, _ := TakeJob(c.Name, jobID, progressCh); !ok {
+			botLog.WithField("job_id", jobID).Warn("bot could not take reserved job")
+			failed[jobID] = true
+			UnreserveJob(c.Name, jobID)
+			continue
+		}
+
+		if !botSleep(ctx, travelDelay) {
+			return
+		}
+		if ok, _, _, _, _, _ := FinishJob(c.Name, jobID, FinishReport{}, progressCh); !ok {
+			botLog.WithField("job_id", jobID).Warn("bot could not finish job")
+			continue
+		}
+		botLog.WithField("job_id", jobID).Info("bot finished job")
+	}
+}
+
+func findBotJob(c BotConfig, failed map[string]bool) string {
+	jobLock.Lock()
+	defer jobLock.Unlock()
+
+	for _, logicStation := range SortedStations() {
+		for _, j := range logicStation.JobQueue {
+			if j.jobSpawned && !j.jobReserved && !failed[j.ID] && c.Matches(j) {
+				return j.ID
+			}
+		}
+	}
+
+	return ""
+}
@@ -1,10 +1,45 @@
 package sharedjob
 
-import "github.com/sirupsen/logrus"
+import (
+	"slices"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
 
 type (
 	CargoType     string
 	CargoCategory string
+	CarType       string
+	// CargoInfo is the static knowledge about a cargo type. MassPerCar is in tonnes and
+	// HazmatClass names the license needed to haul it, if any.
+	CargoInfo struct {
+		Type        CargoType     `json:"type"`
+		DisplayName string        `json:"display_name"`
+		Category    CargoCategory `json:"category"`
+		CarTypes    []CarType     `json:"car_types"`
+		MassPerCar  float64       `json:"mass_per_car"`
+		HazmatClass License       `json:"hazmat_class,omitempty"`
+		BaseWage    int           `json:"base_wage"`
+	}
+)
+
+const (
+	CarFlatbed         CarType = "Flatbed"
+	CarFlatbedStakes   CarType = "FlatbedStakes"
+	CarFlatbedMilitary CarType = "FlatbedMilitary"
+	CarAutorack        CarType = "Autorack"
+	CarTankOil         CarType = "TankOil"
+	CarTankGas         CarType = "TankGas"
+	CarTankChem        CarType = "TankChem"
+	CarTankFood        CarType = "TankFood"
+	CarStock           CarType = "Stock"
+	CarBoxcar          CarType = "Boxcar"
+	CarBoxcarMilitary  CarType = "BoxcarMilitary"
+	CarRefrigerator    CarType = "Refrigerator"
+	CarHopper          CarType = "Hopper"
+	CarGondola         CarType = "Gondola"
+	CarNuclearFlask    CarType = "NuclearFlask"
 )
 
 const (
@@ -92,102 +127,143 @@ const (
 	CategoryComplex CargoCategory = "Complex"
 )
 
-var cargoCategory = map[CargoType]CargoCategory{
-	None:                CategoryRaw,
-	Coal:                CategoryRaw,
-	IronOre:             CategoryRaw,
-	CrudeOil:            CategoryDanger,
-	Diesel:              CategoryDanger,
-	Gasoline:            CategoryDanger,
-	Methane:             CategoryDanger,
-	Logs:                CategoryRaw,
-	Boards:              CategoryEasy,
-	Plywood:             CategoryEasy,
-	Wheat:               CategoryRaw,
-	Corn:                CategoryRaw,
-	Pigs:                CategoryRaw,
-	Cows:                CategoryRaw,
-	Chickens:            CategoryRaw,
-	Sheep:               CategoryRaw,
-	Goats:               CategoryRaw,
-	Bread:               CategoryEasy,
-	DairyProducts:       CategoryEasy,
-	MeatProducts:        CategoryEasy,
-	CannedFood:          CategoryEasy,
-	CatFood:             CategoryEasy,
-	SteelRolls:          CategoryEasy,
-	SteelBillets:        CategoryEasy,
-	SteelSlabs:          CategoryEasy,
-	SteelBentPlates:     CategoryEasy,
-	SteelRails:          CategoryEasy,
-	ScrapMetal:          CategoryRaw,
-	ElectronicsIskar:    CategoryComplex,
-	ElectronicsKrugmann: CategoryComplex,
-	ElectronicsAAG:      CategoryComplex,
-	ElectronicsNovae:    CategoryComplex,
-	ElectronicsTraeg:    CategoryComplex,
-	ToolsIskar:          CategoryComplex,
-	ToolsBrohm:          CategoryComplex,
-	ToolsAAG:            CategoryComplex,
-	ToolsNovae:          CategoryComplex,
-	ToolsTraeg:          CategoryComplex,
-	Furniture:           CategoryComplex,
-	Pipes:               CategoryEasy,
-	ClothingObco:        CategoryComplex,
-	ClothingNeoGamma:    CategoryComplex,
-	ClothingNovae:       CategoryComplex,
-	ClothingTraeg:       CategoryComplex,
-	Medicine:            CategoryComplex,
-	ChemicalsIskar:      CategoryComplex,
-	ChemicalsSperex:     CategoryComplex,
-	NewCars:             CategoryComplex,
-	ImportedNewCars:     CategoryComplex,
-	Tractors:            CategoryComplex,
-	Excavators:          CategoryComplex,
-	Alcohol:             CategoryEasy,
-	Acetylene:           CategoryDanger,
-	CryoOxygen:          CategoryDanger,
-	CryoHydrogen:        CategoryDanger,
-	Argon:               CategoryDanger,
-	Nitrogen:            CategoryDanger,
-	Ammonia:             CategoryDanger,
-	SodiumHydroxide:     CategoryDanger,
-	SpentNuclearFuel:    CategoryDanger,
-	Ammunition:          CategoryDanger,
-	Biohazard:           CategoryDanger,
-	Tanks:               CategoryDanger,
-	MilitaryTrucks:      CategoryComplex,
-	MilitarySupplies:    CategoryComplex,
-	EmptySunOmni:        CategoryRaw,
-	EmptyIskar:          CategoryRaw,
-	EmptyObco:           CategoryRaw,
-	EmptyGoorsk:         CategoryRaw,
-	EmptyKrugmann:       CategoryRaw,
-	EmptyBrohm:          CategoryRaw,
-	EmptyAAG:            CategoryRaw,
-	EmptySperex:         CategoryRaw,
-	EmptyNovae:          CategoryRaw,
-	EmptyTraeg:          CategoryRaw,
-	EmptyChemlek:        CategoryRaw,
-	EmptyNeoGamma:       CategoryRaw,
+var cargoCatalogue = map[CargoType]CargoInfo{
+	None:                {DisplayName: "None", Category: CategoryRaw, MassPerCar: 0},
+	Coal:                {DisplayName: "Coal", Category: CategoryRaw, CarTypes: []CarType{CarHopper, CarGondola}, MassPerCar: 60},
+	IronOre:             {DisplayName: "Iron ore", Category: CategoryRaw, CarTypes: []CarType{CarHopper, CarGondola}, MassPerCar: 60},
+	CrudeOil:            {DisplayName: "Crude oil", Category: CategoryDanger, CarTypes: []CarType{CarTankOil}, MassPerCar: 50, HazmatClass: LicenseHazmat1},
+	Diesel:              {DisplayName: "Diesel", Category: CategoryDanger, CarTypes: []CarType{CarTankOil}, MassPerCar: 45, HazmatClass: LicenseHazmat1},
+	Gasoline:            {DisplayName: "Gasoline", Category: CategoryDanger, CarTypes: []CarType{CarTankOil}, MassPerCar: 40, HazmatClass: LicenseHazmat1},
+	Methane:             {DisplayName: "Methane", Category: CategoryDanger, CarTypes: []CarType{CarTankGas}, MassPerCar: 25, HazmatClass: LicenseHazmat1},
+	Logs:                {DisplayName: "Logs", Category: CategoryRaw, CarTypes: []CarType{CarFlatbedStakes}, MassPerCar: 35},
+	Boards:              {DisplayName: "Boards", Category: CategoryEasy, CarTypes: []CarType{CarFlatbedStakes}, MassPerCar: 30},
+	Plywood:             {DisplayName: "Plywood", Category: CategoryEasy, CarTypes: []CarType{CarFlatbedStakes, CarBoxcar}, MassPerCar: 30},
+	Wheat:               {DisplayName: "Wheat", Category: CategoryRaw, CarTypes: []CarType{CarHopper}, MassPerCar: 45},
+	Corn:                {DisplayName: "Corn", Category: CategoryRaw, CarTypes: []CarType{CarHopper}, MassPerCar: 45},
+	Pigs:                {DisplayName: "Pigs", Category: CategoryRaw, CarTypes: []CarType{CarStock}, MassPerCar: 10},
+	Cows:                {DisplayName: "Cows", Category: CategoryRaw, CarTypes: []CarType{CarStock}, MassPerCar: 12},
+	Chickens:            {DisplayName: "Chickens", Category: CategoryRaw, CarTypes: []CarType{CarStock}, MassPerCar: 5},
+	Sheep:               {DisplayName: "Sheep", Category: CategoryRaw, CarTypes: []CarType{CarStock}, MassPerCar: 8},
+	Goats:               {DisplayName: "Goats", Category: CategoryRaw, CarTypes: []CarType{CarStock}, MassPerCar: 8},
+	Bread:               {DisplayName: "Bread", Category: CategoryEasy, CarTypes: []CarType{CarRefrigerator}, MassPerCar: 15},
+	DairyProducts:       {DisplayName: "Dairy products", Category: CategoryEasy, CarTypes: []CarType{CarRefrigerator}, MassPerCar: 20},
+	MeatProducts:        {DisplayName: "Meat products", Category: CategoryEasy, CarTypes: []CarType{CarRefrigerator}, MassPerCar: 20},
+	CannedFood:          {DisplayName: "Canned food", Category: CategoryEasy, CarTypes: []CarType{CarBoxcar}, MassPerCar: 25},
+	CatFood:             {DisplayName: "Cat food", Category: CategoryEasy, CarTypes: []CarType{CarBoxcar}, MassPerCar: 25},
+	SteelRolls:          {DisplayName: "Steel rolls", Category: CategoryEasy, CarTypes: []CarType{CarFlatbed}, MassPerCar: 50},
+	SteelBillets:        {DisplayName: "Steel billets", Category: CategoryEasy, CarTypes: []CarType{CarFlatbed}, MassPerCar: 55},
+	SteelSlabs:          {DisplayName: "Steel slabs", Category: CategoryEasy, CarTypes: []CarType{CarFlatbed}, MassPerCar: 55},
+	SteelBentPlates:     {DisplayName: "Steel bent plates", Category: CategoryEasy, CarTypes: []CarType{CarFlatbed}, MassPerCar: 45},
+	SteelRails:          {DisplayName: "Steel rails", Category: CategoryEasy, CarTypes: []CarType{CarFlatbed}, MassPerCar: 50},
+	ScrapMetal:          {DisplayName: "Scrap metal", Category: CategoryRaw, CarTypes: []CarType{CarGondola}, MassPerCar: 40},
+	ElectronicsIskar:    {DisplayName: "Electronics (Iskar)", Category: CategoryComplex, CarTypes: []CarType{CarFlatbed}, MassPerCar: 15},
+	ElectronicsKrugmann: {DisplayName: "Electronics (Krugmann)", Category: CategoryComplex, CarTypes: []CarType{CarFlatbed}, MassPerCar: 15},
+	ElectronicsAAG:      {DisplayName: "Electronics (AAG)", Category: CategoryComplex, CarTypes: []CarType{CarFlatbed}, MassPerCar: 15},
+	ElectronicsNovae:    {DisplayName: "Electronics (Novae)", Category: CategoryComplex, CarTypes: []CarType{CarFlatbed}, MassPerCar: 15},
+	ElectronicsTraeg:    {DisplayName: "Electronics (Traeg)", Category: CategoryComplex, CarTypes: []CarType{CarFlatbed}, MassPerCar: 15},
+	ToolsIskar:          {DisplayName: "Tools (Iskar)", Category: CategoryComplex, CarTypes: []CarType{CarFlatbed}, MassPerCar: 20},
+	ToolsBrohm:          {DisplayName: "Tools (Brohm)", Category: CategoryComplex, CarTypes: []CarType{CarFlatbed}, MassPerCar: 20},
+	ToolsAAG:            {DisplayName: "Tools (AAG)", Category: CategoryComplex, CarTypes: []CarType{CarFlatbed}, MassPerCar: 20},
+	ToolsNovae:          {DisplayName: "Tools (Novae)", Category: CategoryComplex, CarTypes: []CarType{CarFlatbed}, MassPerCar: 20},
+	ToolsTraeg:          {DisplayName: "Tools (Traeg)", Category: CategoryComplex, CarTypes: []CarType{CarFlatbed}, MassPerCar: 20},
+	Furniture:           {DisplayName: "Furniture", Category: CategoryComplex, CarTypes: []CarType{CarBoxcar}, MassPerCar: 15},
+	Pipes:               {DisplayName: "Pipes", Category: CategoryEasy, CarTypes: []CarType{CarFlatbedStakes}, MassPerCar: 35},
+	ClothingObco:        {DisplayName: "Clothing (Obco)", Category: CategoryComplex, CarTypes: []CarType{CarFlatbed}, MassPerCar: 10},
+	ClothingNeoGamma:    {DisplayName: "Clothing (NeoGamma)", Category: CategoryComplex, CarTypes: []CarType{CarFlatbed}, MassPerCar: 10},
+	ClothingNovae:       {DisplayName: "Clothing (Novae)", Category: CategoryComplex, CarTypes: []CarType{CarFlatbed}, MassPerCar: 10},
+	ClothingTraeg:       {DisplayName: "Clothing (Traeg)", Category: CategoryComplex, CarTypes: []CarType{CarFlatbed}, MassPerCar: 10},
+	Medicine:            {DisplayName: "Medicine", Category: CategoryComplex, CarTypes: []CarType{CarRefrigerator}, MassPerCar: 10},
+	ChemicalsIskar:      {DisplayName: "Chemicals (Iskar)", Category: CategoryComplex, CarTypes: []CarType{CarFlatbed}, MassPerCar: 20},
+	ChemicalsSperex:     {DisplayName: "Chemicals (Sperex)", Category: CategoryComplex, CarTypes: []CarType{CarFlatbed}, MassPerCar: 20},
+	NewCars:             {DisplayName: "New cars", Category: CategoryComplex, CarTypes: []CarType{CarAutorack}, MassPerCar: 12},
+	ImportedNewCars:     {DisplayName: "Imported new cars", Category: CategoryComplex, CarTypes: []CarType{CarAutorack}, MassPerCar: 12},
+	Tractors:            {DisplayName: "Tractors", Category: CategoryComplex, CarTypes: []CarType{CarFlatbed}, MassPerCar: 25},
+	Excavators:          {DisplayName: "Excavators", Category: CategoryComplex, CarTypes: []CarType{CarFlatbed}, MassPerCar: 40},
+	Alcohol:             {DisplayName: "Alcohol", Category: CategoryEasy, CarTypes: []CarType{CarTankFood}, MassPerCar: 40},
+	Acetylene:           {DisplayName: "Acetylene", Category: CategoryDanger, CarTypes: []CarType{CarTankGas}, MassPerCar: 20, HazmatClass: LicenseHazmat2},
+	CryoOxygen:          {DisplayName: "Cryogenic oxygen", Category: CategoryDanger, CarTypes: []CarType{CarTankGas}, MassPerCar: 45, HazmatClass: LicenseHazmat2},
+	CryoHydrogen:        {DisplayName: "Cryogenic hydrogen", Category: CategoryDanger, CarTypes: []CarType{CarTankGas}, MassPerCar: 10, HazmatClass: LicenseHazmat2},
+	Argon:               {DisplayName: "Argon", Category: CategoryDanger, CarTypes: []CarType{CarTankGas}, MassPerCar: 35, HazmatClass: LicenseHazmat2},
+	Nitrogen:            {DisplayName: "Nitrogen", Category: CategoryDanger, CarTypes: []CarType{CarTankGas}, MassPerCar: 30, HazmatClass: LicenseHazmat2},
+	Ammonia:             {DisplayName: "Ammonia", Category: CategoryDanger, CarTypes: []CarType{CarTankChem}, MassPerCar: 30, HazmatClass: LicenseHazmat2},
+	SodiumHydroxide:     {DisplayName: "Sodium hydroxide", Category: CategoryDanger, CarTypes: []CarType{CarTankChem}, MassPerCar: 45, HazmatClass: LicenseHazmat2},
+	SpentNuclearFuel:    {DisplayName: "Spent nuclear fuel", Category: CategoryDanger, CarTypes: []CarType{CarNuclearFlask}, MassPerCar: 30, HazmatClass: LicenseHazmat3},
+	Ammunition:          {DisplayName: "Ammunition", Category: CategoryDanger, CarTypes: []CarType{CarBoxcarMilitary}, MassPerCar: 20, HazmatClass: LicenseMilitary},
+	Biohazard:           {DisplayName: "Biohazard", Category: CategoryDanger, CarTypes: []CarType{CarRefrigerator}, MassPerCar: 15, HazmatClass: LicenseHazmat3},
+	Tanks:               {DisplayName: "Tanks", Category: CategoryDanger, CarTypes: []CarType{CarFlatbedMilitary}, MassPerCar: 45, HazmatClass: LicenseMilitary},
+	MilitaryTrucks:      {DisplayName: "Military trucks", Category: CategoryComplex, CarTypes: []CarType{CarFlatbedMilitary}, MassPerCar: 20},
+	MilitarySupplies:    {DisplayName: "Military supplies", Category: CategoryComplex, CarTypes: []CarType{CarBoxcarMilitary}, MassPerCar: 15},
+	EmptySunOmni:        {DisplayName: "Empty cars (SunOmni)", Category: CategoryRaw, CarTypes: []CarType{CarTankOil}, MassPerCar: 0},
+	EmptyIskar:          {DisplayName: "Empty cars (Iskar)", Category: CategoryRaw, CarTypes: []CarType{CarFlatbed}, MassPerCar: 0},
+	EmptyObco:           {DisplayName: "Empty cars (Obco)", Category: CategoryRaw, CarTypes: []CarType{CarStock, CarTankFood}, MassPerCar: 0},
+	EmptyGoorsk:         {DisplayName: "Empty cars (Goorsk)", Category: CategoryRaw, CarTypes: []CarType{CarHopper, CarGondola}, MassPerCar: 0},
+	EmptyKrugmann:       {DisplayName: "Empty cars (Krugmann)", Category: CategoryRaw, CarTypes: []CarType{CarBoxcar}, MassPerCar: 0},
+	EmptyBrohm:          {DisplayName: "Empty cars (Brohm)", Category: CategoryRaw, CarTypes: []CarType{CarFlatbedStakes}, MassPerCar: 0},
+	EmptyAAG:            {DisplayName: "Empty cars (AAG)", Category: CategoryRaw, CarTypes: []CarType{CarBoxcarMilitary, CarFlatbedMilitary}, MassPerCar: 0},
+	EmptySperex:         {DisplayName: "Empty cars (Sperex)", Category: CategoryRaw, CarTypes: []CarType{CarNuclearFlask}, MassPerCar: 0},
+	EmptyNovae:          {DisplayName: "Empty cars (Novae)", Category: CategoryRaw, CarTypes: []CarType{CarAutorack}, MassPerCar: 0},
+	EmptyTraeg:          {DisplayName: "Empty cars (Traeg)", Category: CategoryRaw, CarTypes: []CarType{CarRefrigerator}, MassPerCar: 0},
+	EmptyChemlek:        {DisplayName: "Empty cars (Chemlek)", Category: CategoryRaw, CarTypes: []CarType{CarTankChem}, MassPerCar: 0},
+	EmptyNeoGamma:       {DisplayName: "Empty cars (NeoGamma)", Category: CategoryRaw, CarTypes: []CarType{CarTankGas}, MassPerCar: 0},
 }
 
 func (ct CargoType) String() string {
 	return string(ct)
 }
 
+func (c CargoCategory) String() string {
+	return string(c)
+}
+
+func (ct CarType) String() string {
+	return string(ct)
+}
+
 func (c CargoType) BaseWage() int {
-	switch cargoCategory[c] {
-	case CategoryRaw:
-		return 400
-	case CategoryDanger:
-		return 1000
-	case CategoryEasy:
-		return 600
-	case CategoryComplex:
-		return 800
+	info, ok := cargoCatalogue[c]
+	if !ok {
+		logrus.WithField("cargo", c).Info("unmapped cargo type for wage calc")
+		return 0
+	}
+
+	return applyWageMultiplier(economy.CategoryWages[info.Category])
+}
+
+func (c CargoType) Category() CargoCategory {
+	return cargoCatalogue[c].Category
+}
+
+// Info returns the catalogue entry with the base wage of the active economy settings
+func (c CargoType) Info() (CargoInfo, bool) {
+	info, ok := cargoCatalogue[c]
+	if !ok {
+		return CargoInfo{}, false
+	}
+
+	info.Type = c
+	info.CarTypes = slices.Clone(info.CarTypes)
+	info.BaseWage = c.BaseWage()
+	return info, true
+}
+
+// CargoCatalogue lists all cargo types except None sorted by type
+func CargoCatalogue() []CargoInfo {
+	catalogue := make([]CargoInfo, 0, len(cargoCatalogue))
+	for cargo := range cargoCatalogue {
+		if cargo == None {
+			continue
+		}
+
+		info, _ := cargo.Info()
+		catalogue = append(catalogue, info)
 	}
+	slices.SortFunc(catalogue, func(a, b CargoInfo) int {
+		return strings.Compare(string(a.Type), string(b.Type))
+	})
+
+	return catalogue
+}
 
-	logrus.WithField("cargo", c).Info("unmapped cargo type for wage calc")
-	return 0
+func applyWageMultiplier(wage int) int {
+	return int(float64(wage) * economy.WageMultiplier)
 }
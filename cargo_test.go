@@ -0,0 +1,22 @@
+package sharedjob
+
+import "testing"
+
+func TestCargoCatalogueComplete(t *testing.T) {
+	for _, info := range CargoCatalogue() {
+		if info.DisplayName == "" || len(info.CarTypes) == 0 || (info.MassPerCar <= 0 && !info.Type.IsEmpty()) {
+			t.Errorf("incomplete catalogue entry %+v", info)
+		}
+		if info.Category == CategoryDanger && info.HazmatClass == "" {
+			t.Errorf("danger cargo %s without hazmat class", info.Type)
+		}
+		for _, carType := range info.CarTypes {
+			if info.Type.IsEmpty() && emptyCargoByCarType[carType] != info.Type {
+				t.Errorf("empty cargo %s lists car type %s owned by %s", info.Type, carType, emptyCargoByCarType[carType])
+			}
+		}
+		if info.BaseWage != info.Type.BaseWage() {
+			t.Errorf("catalogue base wage %d of %s differs from wage code %d", info.BaseWage, info.Type, info.Type.BaseWage())
+		}
+	}
+}
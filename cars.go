@@ -0,0 +1,80 @@
+package sharedjob
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Car is a single car of a job. All clients spawn exactly these cars so the consists
+// match across game instances.
+type Car struct {
+	ID     string  `json:"id"`
+	Type   CarType `json:"type"`
+	Livery string  `json:"livery"`
+}
+
+var carLiveries = map[CarType][]string{
+	CarFlatbed:         {"Brown", "Red", "Blue"},
+	CarFlatbedStakes:   {"Brown", "Green"},
+	CarFlatbedMilitary: {"Military"},
+	CarAutorack:        {"Red", "Blue", "Green", "Yellow"},
+	CarTankOil:         {"Black", "White", "Orange"},
+	CarTankGas:         {"White", "Yellow"},
+	CarTankChem:        {"Blue", "White"},
+	CarTankFood:        {"Silver"},
+	CarStock:           {"Brown", "Red", "Green"},
+	CarBoxcar:          {"Brown", "Red", "Pink", "Green"},
+	CarBoxcarMilitary:  {"Military"},
+	CarRefrigerator:    {"White", "Blue"},
+	CarHopper:          {"Brown", "Teal", "Yellow"},
+	CarGondola:         {"Red", "Green", "Gray"},
+	CarNuclearFlask:    {"Yellow"},
+}
+
+// lastCarNum makes car IDs unique for the lifetime of a world
+var lastCarNum int
+
+// newCar picks car type and livery for the cargo with the world RNG
+func newCar(cargo CargoType) Car {
+	carTypes := cargoCatalogue[cargo].CarTypes
+	carType := CarFlatbed
+	if len(carTypes) > 0 {
+		carType = carTypes[worldRand.Intn(len(carTypes))]
+	}
+	liveries := carLiveries[carType]
+
+	lastCarNum++
+	return Car{
+		ID:     fmt.Sprintf("C%06d", lastCarNum),
+		Type:   carType,
+		Livery: liveries[worldRand.Intn(len(liveries))],
+	}
+}
+
+// resizeCars keeps existing cars and only adds or drops cars at the end
+func (j *Job) resizeCars() {
+	if len(j.Cars) > j.CarCount {
+		j.Cars = j.Cars[:j.CarCount]
+		return
+	}
+
+	for len(j.Cars) < j.CarCount {
+		j.Cars = append(j.Cars, newCar(j.CargoType))
+	}
+}
+
+// reusableCars picks up to count cars whose type can carry the cargo
+func reusableCars(cars []Car, cargo CargoType, count int) []Car {
+	carTypes := cargoCatalogue[cargo].CarTypes
+	reusable := make([]Car, 0, count)
+	for _, car := range cars {
+		if len(reusable) >= count {
+			break
+		}
+		if slices.Contains(carTypes, car.Type) {
+			reusable = append(reusable, car)
+		}
+	}
+
+	return reusable
+}
@@ -0,0 +1,117 @@
+package sharedjob
+
+import (
+	"fmt"
+	"slices"
+)
+
+type (
+	ChainLegState string
+	ChainLeg      struct {
+		Job   Job           `json:"job"`
+		State ChainLegState `json:"state"`
+		User  string        `json:"user,omitempty"`
+	}
+	Chain struct {
+		ID       string     `json:"id"`
+		Legs     []ChainLeg `json:"legs"`
+		Complete bool       `json:"complete"`
+	}
+)
+
+const (
+	ChainLegQueued   ChainLegState = "queued"
+	ChainLegSpawned  ChainLegState = "spawned"
+	ChainLegReserved ChainLegState = "reserved"
+	ChainLegActive   ChainLegState = "active"
+	ChainLegFinished ChainLegState = "finished"
+)
+
+// chainLegOrder is the order cargo moves through a chain
+var chainLegOrder = []JobType{ShuntingLoadJobType, FreightJobType, ShuntingUnloadJobType}
+
+func (s ChainLegState) String() string {
+	return string(s)
+}
+
+// GetChain collects every leg of the chain from the archive and the station queues. The legs
+// hold copies of the jobs.
+func GetChain(chainID string) (Chain, error) {
+	jobLock.Lock()
+	defer jobLock.Unlock()
+
+	chain := buildChain(chainID)
+	if len(chain.Legs) == 0 {
+		return Chain{}, fmt.Errorf("chain %s not found", chainID)
+	}
+
+	return chain, nil
+}
+
+// buildChain expects jobLock to be held
+func buildChain(chainID string) Chain {
+	chain := Chain{ID: chainID, Legs: make([]ChainLeg, 0, len(chainLegOrder))}
+	for _, j := range archivedByChain[chainID] {
+		leg := ChainLeg{Job: j.snapshot(), State: ChainLegFinished}
+		if finished, ok := j.finishedEvent(); ok {
+			leg.User = finished.User
+		}
+		chain.Legs = append(chain.Legs, leg)
+	}
+	for _, logicStation := range SortedStations() {
+		for _, j := range logicStation.JobQueue {
+			if j.ChainID == chainID {
+				chain.Legs = append(chain.Legs, ChainLeg{Job: j.snapshot(), State: j.chainLegState(), User: j.jobAssignedUser})
+			}
+		}
+	}
+
+	slices.SortStableFunc(chain.Legs, func(a, b ChainLeg) int {
+		return slices.Index(chainLegOrder, a.Job.JobType) - slices.Index(chainLegOrder, b.Job.JobType)
+	})
+
+	chain.Complete = len(chain.Legs) == len(chainLegOrder)
+	for i, leg := range chain.Legs {
+		if leg.State != ChainLegFinished || i >= len(chainLegOrder) || leg.Job.JobType != chainLegOrder[i] {
+			chain.Complete = false
+		}
+	}
+
+	return chain
+}
+
+func (j *Job) chainLegState() ChainLegState {
+	switch {
+	case j.jobActive:
+		return ChainLegActive
+	case j.jobReserved:
+		return ChainLegReserved
+	case j.jobSpawned:
+		return ChainLegSpawned
+	}
+
+	return ChainLegQueued
+}
+
+// soloChainBonus returns the bonus for a player who finished every leg of a complete chain
+// themselves. Expects jobLock to be held.
+func soloChainBonus(chainID, userName string) int {
+	if economy.ChainBonus <= 0 {
+		return 0
+	}
+
+	chain := buildChain(chainID)
+	if !chain.Complete {
+		return 0
+	}
+
+	wages := 0
+	for _, leg := range chain.Legs {
+		if leg.User != userName {
+			return 0
+		}
+		wages += leg.Job.Wage
+	}
+
+	return int(float64(wages) * economy.ChainBonus)
+}
@@ -0,0 +1,133 @@
+package sharedjob
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestFollowUpKeepsCars(t *testing.T) {
+	ResetWorld()
+	SeedWorld(5)
+	Setup()
+	GrantLicenses("test", AllLicenses()...)
+
+	progressCh := make(chan ProgressMessage, 100)
+	load := GetAllStationJobs(StationCM)[0]
+	ReserveJob("test", load.ID)
+	TakeJob("test", load.ID, progressCh)
+	ok, _, _, _, newJobs, _ := FinishJob("test", load.ID, FinishReport{}, progressCh)
+	if !ok || len(newJobs) == 0 {
+		t.Fatalf("expected a follow-up job, got %v", newJobs)
+	}
+
+	freight := newJobs[0]
+	if freight.JobType != FreightJobType {
+		t.Fatalf("expected freight follow-up, got %s", freight.JobType)
+	}
+	if freight.ChainID != load.ChainID {
+		t.Errorf("expected chain %s, got %s", load.ChainID, freight.ChainID)
+	}
+	if freight.StartingTrack != load.TargetTrack {
+		t.Errorf("expected follow-up to start on %s, got %s", load.TargetTrack, freight.StartingTrack)
+	}
+	if fmt.Sprint(freight.Cars) != fmt.Sprint(load.Cars) {
+		t.Errorf("expected cars %v, got %v", load.Cars, freight.Cars)
+	}
+}
+
+func TestSoloChainPaysBonus(t *testing.T) {
+	ResetWorld()
+	SeedWorld(5)
+	Setup()
+	GrantLicenses("test", AllLicenses()...)
+
+	progressCh := make(chan ProgressMessage, 100)
+	jobID := GetAllStationJobs(StationCM)[0].ID
+	chainID := jobID
+	for _, expected := range []JobType{ShuntingLoadJobType, FreightJobType, ShuntingUnloadJobType} {
+		ReserveJob("test", jobID)
+		TakeJob("test", jobID, progressCh)
+		ok, _, _, _, newJobs, _ := FinishJob("test", jobID, FinishReport{}, progressCh)
+		if !ok {
+			t.Fatalf("could not finish %s leg %s", expected, jobID)
+		}
+		if len(newJobs) > 0 && newJobs[0].ChainID == chainID {
+			jobID = newJobs[0].ID
+		}
+	}
+
+	chain, err := GetChain(chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !chain.Complete {
+		t.Fatalf("expected complete chain, got %+v", chain)
+	}
+
+	bonusBooked := false
+	for _, entry := range GetWallet("test").Ledger {
+		bonusBooked = bonusBooked || entry.Reason == "chain completion bonus"
+	}
+	if !bonusBooked {
+		t.Error("expected a chain completion bonus in the ledger")
+	}
+}
+
+func TestProcessorOutputKeepsUnloadedCars(t *testing.T) {
+	ResetWorld()
+	SeedWorld(5)
+	Setup()
+	GrantLicenses("test", AllLicenses()...)
+
+	progressCh := make(chan ProgressMessage, 100)
+	var jobID string
+	for _, j := range GetAllStationJobs(StationOWC) {
+		if j.CargoType == CrudeOil {
+			jobID = j.ID
+		}
+	}
+	if jobID == "" {
+		t.Fatal("expected a crude oil load job at OWC")
+	}
+
+	var unload *Job
+	for _, expected := range []JobType{ShuntingLoadJobType, FreightJobType, ShuntingUnloadJobType} {
+		j, err := FindJob(jobID)
+		if err != nil || j.JobType != expected {
+			t.Fatalf("expected %s leg, got %+v (%v)", expected, j, err)
+		}
+		if expected == ShuntingUnloadJobType {
+			unload = &j
+			// keep the diesel output from merging into a waiting job
+			hb := AllStations[StationHB]
+			hb.JobQueue = slices.DeleteFunc(hb.JobQueue, func(waiting *Job) bool { return waiting.CargoType == Diesel })
+		}
+
+		ReserveJob("test", jobID)
+		TakeJob("test", jobID, progressCh)
+		ok, _, _, _, newJobs, _ := FinishJob("test", jobID, FinishReport{}, progressCh)
+		if !ok {
+			t.Fatalf("could not finish %s leg %s", expected, jobID)
+		}
+		if len(newJobs) > 0 {
+			jobID = newJobs[0].ID
+		}
+	}
+
+	var load *Job
+	for _, j := range AdvanceWorldClock(economy.ProductionDuration(), progressCh) {
+		if j.StartingStationName == StationHB && j.CargoType == Diesel {
+			load = j
+		}
+	}
+	if load == nil {
+		t.Fatal("expected a diesel load job at HB")
+	}
+	if load.StartingTrack != unload.TargetTrack {
+		t.Errorf("expected load job to start on %s, got %s", unload.TargetTrack, load.StartingTrack)
+	}
+	if fmt.Sprint(load.Cars) != fmt.Sprint(unload.Cars) {
+		t.Errorf("expected cars %v, got %v", unload.Cars, load.Cars)
+	}
+}
@@ -0,0 +1,51 @@
+package sharedjob
+
+import (
+	"slices"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// worldTime is the simulated time that drives processor production. The server advances
+// it with the wall clock, the simulator and tests advance it by hand. It carries no monotonic
+// reading so that it survives a save and load unchanged.
+var worldTime = time.Now().Round(0)
+
+// WorldTime returns the current time of the world clock.
+func WorldTime() time.Time {
+	return worldTime
+}
+
+// AdvanceWorldClock moves the world clock forward and finishes all production that is due
+// by then. Returns the load jobs created from the produced cargo.
+func AdvanceWorldClock(d time.Duration, progressCh chan<- ProgressMessage) []*Job {
+	jobLock.Lock()
+	defer jobLock.Unlock()
+
+	worldTime = worldTime.Add(d)
+
+	newJobs := make([]*Job, 0)
+	producingStations := make([]StationID, 0)
+	for _, logicStation := range SortedStations() {
+		stationJobs, produced := logicStation.completeProduction()
+		if produced {
+			newJobs = append(newJobs, stationJobs...)
+			producingStations = append(producingStations, logicStation.ID)
+		}
+	}
+	if len(producingStations) <= 0 {
+		return newJobs
+	}
+
+	updateData := updateAllJobs(nil)
+	// output merged into a waiting job changes the station without any spawn
+	notifyStationIDs := append(producingStations, updateData.notifyStationIDs...)
+	slices.Sort(notifyStationIDs)
+	for _, sid := range slices.Compact(notifyStationIDs) {
+		logrus.WithField("station_id", sid).Info("notifying station after production")
+		progressCh <- ProgressMessage{StationID: sid}
+	}
+
+	return newJobs
+}
@@ -0,0 +1,62 @@
+package sharedjob
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProductionWaitsForWorldClock(t *testing.T) {
+	ResetWorld()
+	SeedWorld(8)
+	Setup()
+
+	defaultEconomy := economy
+	defer func() { economy = defaultEconomy }()
+	economy.ProductionCapacity = 1
+
+	progressCh := make(chan ProgressMessage, 100)
+	sm := AllStations[StationSM]
+	countLoadJobs := func() int {
+		count := 0
+		for _, j := range sm.JobQueue {
+			if j.JobType == ShuntingLoadJobType {
+				count++
+			}
+		}
+		return count
+	}
+
+	sm.ProcessJob(sm.AddJob(StationSM, ShuntingUnloadJobType, 2, Coal, "test delivery"))
+	sm.ProcessJob(sm.AddJob(StationSM, ShuntingUnloadJobType, 4, IronOre, "test delivery"))
+	if countLoadJobs() != 0 {
+		t.Fatalf("expected no output before the production time, got %d load jobs", countLoadJobs())
+	}
+
+	info, err := GetStationInfo(StationSM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Production) != 1 || info.Production[0].CarCount != 1 {
+		t.Fatalf("expected one batch of 1 car limited by capacity, got %+v", info.Production)
+	}
+	if !info.Production[0].ReadyAt.Equal(WorldTime().Add(economy.ProductionDuration())) {
+		t.Errorf("expected batch ready at %s, got %s", WorldTime().Add(economy.ProductionDuration()), info.Production[0].ReadyAt)
+	}
+	if info.InputBuffer[Coal] != 1 || info.InputBuffer[IronOre] != 2 {
+		t.Errorf("expected input for one more car to wait, got %v", info.InputBuffer)
+	}
+
+	AdvanceWorldClock(economy.ProductionDuration()-time.Minute, progressCh)
+	if countLoadJobs() != 0 {
+		t.Fatalf("expected no output before the production time, got %d load jobs", countLoadJobs())
+	}
+
+	if newJobs := AdvanceWorldClock(time.Minute, progressCh); len(newJobs) != 1 || newJobs[0].CargoType != info.Production[0].CargoType {
+		t.Fatalf("expected one load job with %s, got %v", info.Production[0].CargoType, newJobs)
+	}
+
+	info, _ = GetStationInfo(StationSM)
+	if len(info.Production) != 1 || len(info.InputBuffer) != 0 {
+		t.Errorf("expected the waiting input to start the next batch, got %+v and %v", info.Production, info.InputBuffer)
+	}
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/devnull-twitch/sharedjob-server"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+// Load test against a running server. Opens websocket clients subscribed to random stations
+// while workers progress jobs over REST, then reports notification latency and failures.
+//
+// Latency is measured from the start of the REST call that caused a station notification
+// until a client subscribed to that station receives it. Notifications are matched to their
+// call by job ID and event.
+
+type (
+	actionKey struct {
+		jobID string
+		event sharedjob.JobEventType
+	}
+	stats struct {
+		mu sync.Mutex
+
+		actionAt  map[actionKey]time.Time
+		latencies []time.Duration
+
+		// per client and station
+		expected map[int]map[sharedjob.StationID]int
+		received map[int]map[sharedjob.StationID]int
+
+		subscriptions map[int][]sharedjob.StationID
+
+		closing bool
+
+		failedRequests int
+		failedWSWrites int
+		failedWSReads  int
+		finishedJobs   int
+	}
+)
+
+// actionEvents maps the REST actions to the job event their notifications carry
+var actionEvents = map[string]sharedjob.JobEventType{
+	"take":   sharedjob.JobEventTaken,
+	"finish": sharedjob.JobEventFinished,
+}
+
+func main() {
+	addr := flag.String("addr", "localhost:8083", "server address")
+	clientCount := flag.Int("clients", 50, "number of websocket clients")
+	stationsPerClient := flag.Int("stations-per-client", 3, "number of random stations each client subscribes to")
+	onlyStation := flag.String("station", "", "subscribe every client to this station instead of random ones")
+	workerCount := flag.Int("workers", 5, "number of concurrent REST workers")
+	jobCount := flag.Int("jobs", 100, "number of jobs to finish in total")
+	grace := flag.Duration("grace", 2*time.Second, "time to wait for outstanding notifications")
+	flag.Parse()
+
+	logrus.SetLevel(logrus.WarnLevel)
+
+	s := &stats{
+		actionAt:      make(map[actionKey]time.Time),
+		latencies:     make([]time.Duration, 0),
+		expected:      make(map[int]map[sharedjob.StationID]int),
+		received:      make(map[int]map[sharedjob.StationID]int),
+		subscriptions: make(map[int][]sharedjob.StationID),
+	}
+
+	stationIDs := sharedjob.SortedStationIDs()
+	conns := make([]*websocket.Conn, 0, *clientCount)
+	for i := 0; i < *clientCount; i++ {
+		var subs []sharedjob.StationID
+		if *onlyStation != "" {
+			subs = []sharedjob.StationID{sharedjob.StationID(*onlyStation)}
+		} else {
+			subs = randomStations(stationIDs, *stationsPerClient)
+		}
+
+		conn, err := openClient(*addr, i, subs, s)
+		if err != nil {
+			logrus.WithError(err).WithField("client", i).Error("could not open websocket client")
+			continue
+		}
+		conns = append(conns, conn)
+	}
+
+	// give the server a moment to process all subscriptions
+	time.Sleep(500 * time.Millisecond)
+
+	jobsCh := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < *workerCount; w++ {
+		wg.Add(1)
+		go func(workerNum int) {
+			defer wg.Done()
+			runWorker(*addr, fmt.Sprintf("loadtest-%d", workerNum), stationIDs, jobsCh, s)
+		}(w)
+	}
+
+	for i := 0; i < *jobCount; i++ {
+		jobsCh <- i
+	}
+	close(jobsCh)
+	wg.Wait()
+
+	time.Sleep(*grace)
+	s.mu.Lock()
+	s.closing = true
+	s.mu.Unlock()
+	for _, conn := range conns {
+		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		conn.Close()
+	}
+
+	s.report(len(conns))
+}
+
+func randomStations(stationIDs []sharedjob.StationID, count int) []sharedjob.StationID {
+	shuffled := slices.Clone(stationIDs)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	if count > len(shuffled) {
+		count = len(shuffled)
+	}
+
+	return shuffled[:count]
+}
+
+func openClient(addr string, clientNum int, subs []sharedjob.StationID, s *stats) (*websocket.Conn, error) {
+	conn, _, err := websocket.DefaultDialer.Dial(fmt.Sprintf("ws://%s/ws", addr), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn.WriteJSON(map[string]string{"username": fmt.Sprintf("loadclient-%d", clientNum)}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("could not send welcome message: %w", err)
+	}
+
+	for _, stationID := range subs {
+		if err := conn.WriteJSON(map[string]any{"station_id": stationID}); err != nil {
+			s.mu.Lock()
+			s.failedWSWrites++
+			s.mu.Unlock()
+			continue
+		}
+	}
+
+	s.mu.Lock()
+	s.subscriptions[clientNum] = subs
+	s.expected[clientNum] = make(map[sharedjob.StationID]int)
+	s.received[clientNum] = make(map[sharedjob.StationID]int)
+	s.mu.Unlock()
+
+	go func() {
+		for {
+			msg := sharedjob.ProgressMessage{}
+			if err := conn.ReadJSON(&msg); err != nil {
+				s.mu.Lock()
+				if !s.closing {
+					s.failedWSReads++
+				}
+				s.mu.Unlock()
+				return
+			}
+
+			now := time.Now()
+			s.mu.Lock()
+			if sentAt, ok := s.actionAt[actionKey{jobID: msg.JobID, event: msg.Event}]; ok {
+				s.latencies = append(s.latencies, now.Sub(sentAt))
+			}
+			s.received[clientNum][msg.StationID]++
+			s.mu.Unlock()
+		}
+	}()
+
+	return conn, nil
+}
+
+func runWorker(addr, username string, stationIDs []sharedjob.StationID, jobsCh <-chan int, s *stats) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	licenses, err := fetchLicenses(client, addr, username)
+	if err != nil {
+		s.countFailedRequest()
+		return
+	}
+
+	for range jobsCh {
+		for attempt := 0; attempt < 20; attempt++ {
+			if progressRandomJob(client, addr, username, licenses, stationIDs, s) {
+				break
+			}
+		}
+	}
+}
+
+func fetchLicenses(client *http.Client, addr, username string) ([]sharedjob.License, error) {
+	resp, err := client.Get(fmt.Sprintf("http://%s/wallet/%s", addr, username))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	wallet := sharedjob.Wallet{}
+	if err := json.NewDecoder(resp.Body).Decode(&wallet); err != nil {
+		return nil, err
+	}
+
+	return wallet.Licenses, nil
+}
+
+func progressRandomJob(client *http.Client, addr, username string, licenses []sharedjob.License, stationIDs []sharedjob.StationID, s *stats) bool {
+	stationID := stationIDs[rand.Intn(len(stationIDs))]
+	resp, err := client.Get(fmt.Sprintf("http://%s/station/%s", addr, stationID))
+	if err != nil {
+		s.countFailedRequest()
+		return false
+	}
+	jobs := make([]sharedjob.Job, 0)
+	err = json.NewDecoder(resp.Body).Decode(&jobs)
+	resp.Body.Close()
+	if err != nil {
+		return false
+	}
+
+	// only jobs the worker holds all licenses for
+	jobs = slices.DeleteFunc(jobs, func(j sharedjob.Job) bool {
+		return slices.ContainsFunc(j.LicenseRequired, func(l sharedjob.License) bool {
+			return !slices.Contains(licenses, l)
+		})
+	})
+	if len(jobs) == 0 {
+		return false
+	}
+
+	j := jobs[rand.Intn(len(jobs))]
+	if !postAction(client, addr, username, j.ID, "reserve") {
+		// most likely some other worker got it first
+		return false
+	}
+
+	affected := slices.Compact([]sharedjob.StationID{stationID, j.TargetStationName})
+	for _, action := range []string{"take", "finish"} {
+		s.markAction(j.ID, action)
+		if !postAction(client, addr, username, j.ID, action) {
+			s.countFailedRequest()
+			return false
+		}
+		s.expectNotification(affected)
+	}
+
+	s.mu.Lock()
+	s.finishedJobs++
+	s.mu.Unlock()
+
+	return true
+}
+
+func postAction(client *http.Client, addr, username, jobID, action string) bool {
+	jsonBytes, _ := json.Marshal(&sharedjob.UserIDPayload{Name: username})
+
+	resp, err := client.Post(
+		fmt.Sprintf("http://%s/job/%s/%s", addr, jobID, action),
+		"application/json",
+		bytes.NewBuffer(jsonBytes),
+	)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+
+	return resp.StatusCode == http.StatusOK
+}
+
+func (s *stats) markAction(jobID, action string) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.actionAt[actionKey{jobID: jobID, event: actionEvents[action]}] = now
+}
+
+func (s *stats) countFailedRequest() {
+	s.mu.Lock()
+	s.failedRequests++
+	s.mu.Unlock()
+}
+
+func (s *stats) expectNotification(affected []sharedjob.StationID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for clientNum, subs := range s.subscriptions {
+		for _, stationID := range affected {
+			if slices.Contains(subs, stationID) {
+				s.expected[clientNum][stationID]++
+			}
+		}
+	}
+}
+
+func (s *stats) report(clientCount int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dropped := 0
+	for clientNum, perStation := range s.expected {
+		for stationID, expectedCount := range perStation {
+			if missing := expectedCount - s.received[clientNum][stationID]; missing > 0 {
+				dropped += missing
+			}
+		}
+	}
+
+	fmt.Printf("clients: %d, finished jobs: %d\n", clientCount, s.finishedJobs)
+	fmt.Printf("notifications received: %d, dropped: %d\n", len(s.latencies), dropped)
+	fmt.Printf("failed requests: %d, failed ws writes: %d, failed ws reads: %d\n", s.failedRequests, s.failedWSWrites, s.failedWSReads)
+
+	if len(s.latencies) == 0 {
+		return
+	}
+
+	slices.Sort(s.latencies)
+	fmt.Printf(
+		"latency p50: %s, p90: %s, p99: %s, max: %s\n",
+		percentile(s.latencies, 50),
+		percentile(s.latencies, 90),
+		percentile(s.latencies, 99),
+		s.latencies[len(s.latencies)-1],
+	)
+}
+
+func percentile(sorted []time.Duration, p int) time.Duration {
+	index := len(sorted) * p / 100
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+
+	return sorted[index]
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/devnull-twitch/sharedjob-server"
+)
+
+// loadConfig builds the server config. Precedence: defaults < config file < env < flags.
+func loadConfig() sharedjob.Config {
+	defaults := sharedjob.DefaultConfig()
+
+	configPath := flag.String("config", "", "path to a JSON config file")
+	seed := flag.Int64("seed", defaults.Seed, "world seed for reproducible job generation (0 = random)")
+	botConfigPath := flag.String("bots", defaults.BotsPath, "path to a JSON file with bot player definitions")
+	listenAddr := flag.String("listen", defaults.Network.Listen, "address the HTTP server listens on")
+	statePath := flag.String("state", defaults.Persistence.StatePath, "path to the state file; loaded on start and written on shutdown")
+	logLevel := flag.String("log-level", defaults.LogLevel, "log level")
+	wageMultiplier := flag.Float64("wage-multiplier", defaults.Economy.WageMultiplier, "multiplier applied to all wages")
+	wageModel := flag.String("wage-model", defaults.Economy.WageModel, "wage model: flat or distance")
+	supplyDemand := flag.Bool("supply-demand", defaults.Economy.SupplyDemand, "scale wages with supply and demand")
+	shutdownTimeout := flag.Duration("shutdown-timeout", defaults.ShutdownTimeout(), "max time to wait for a clean shutdown")
+	flag.Parse()
+
+	cfg, err := sharedjob.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// only flags given explicitly override file and env
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "seed":
+			cfg.Seed = *seed
+		case "bots":
+			cfg.BotsPath = *botConfigPath
+		case "listen":
+			cfg.Network.Listen = *listenAddr
+		case "state":
+			cfg.Persistence.StatePath = *statePath
+		case "log-level":
+			cfg.LogLevel = *logLevel
+		case "wage-multiplier":
+			cfg.Economy.WageMultiplier = *wageMultiplier
+		case "wage-model":
+			cfg.Economy.WageModel = *wageModel
+		case "supply-demand":
+			cfg.Economy.SupplyDemand = *supplyDemand
+		case "shutdown-timeout":
+			cfg.Timeouts.ShutdownSeconds = int(*shutdownTimeout / time.Second)
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%s\n", err)
+		os.Exit(1)
+	}
+
+	return cfg
+}
@@ -1,19 +1,62 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"net"
 	"net/http"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
 
 	"github.com/devnull-twitch/sharedjob-server"
 	"github.com/devnull-twitch/sharedjob-server/ui"
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 )
 
 func main() {
+	cfg := loadConfig()
+	sharedjob.ApplyConfig(cfg)
+
+	usedSeed := sharedjob.SeedWorld(cfg.Seed)
 	sharedjob.Setup()
 
+	if cfg.Persistence.StatePath != "" {
+		savedSeed, err := sharedjob.LoadState(cfg.Persistence.StatePath)
+		if err != nil {
+			panic(err)
+		}
+		// LoadState already continued the saved random sequence
+		if savedSeed != 0 {
+			usedSeed = savedSeed
+		}
+	}
+	logrus.WithField("seed", usedSeed).Info("world seed")
+
+	ready := atomic.Bool{}
+
 	clientCh, processorCh := sharedjob.StartWSProcessor()
 
+	botCtx, stopBots := context.WithCancel(context.Background())
+	defer stopBots()
+	waitBots := func() {}
+	if cfg.BotsPath != "" {
+		botConfigs, err := sharedjob.LoadBotConfigs(cfg.BotsPath)
+		if err != nil {
+			panic(err)
+		}
+		waitBots = sharedjob.StartBots(botCtx, botConfigs, processorCh)
+	}
+
+	clockStop := make(chan struct{})
+	clockDone := make(chan struct{})
+	go func() {
+		runWorldClock(processorCh, clockStop)
+		close(clockDone)
+	}()
+
 	r := gin.Default()
 	r.GET("/station/:station", func(c *gin.Context) {
 		username := c.Query("username")
@@ -27,6 +70,15 @@ func main() {
 		}
 		c.JSON(200, jobs)
 	})
+	r.GET("/v1/stations/:station", func(c *gin.Context) {
+		info, err := sharedjob.GetStationInfo(sharedjob.StationID(c.Param("station")))
+		if err != nil {
+			c.Status(http.StatusNotFound)
+			return
+		}
+
+		c.JSON(http.StatusOK, info)
+	})
 	r.POST("/job/:job_id/reserve", func(c *gin.Context) {
 		userPayload := &sharedjob.UserIDPayload{}
 		if err := c.BindJSON(userPayload); err != nil {
@@ -61,19 +113,69 @@ func main() {
 
 		c.Status(http.StatusOK)
 	})
+	r.GET("/job/:job_id/history", func(c *gin.Context) {
+		j, err := sharedjob.FindJob(c.Param("job_id"))
+		if err != nil {
+			c.Status(http.StatusNotFound)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"job":           j,
+			"assigned_user": j.GetAssignedUser(),
+			"history":       j.GetHistory(),
+		})
+	})
+	r.GET("/v1/chains/:chain_id", func(c *gin.Context) {
+		chain, err := sharedjob.GetChain(c.Param("chain_id"))
+		if err != nil {
+			c.Status(http.StatusNotFound)
+			return
+		}
+
+		c.JSON(http.StatusOK, chain)
+	})
+	r.GET("/wallet/:username", func(c *gin.Context) {
+		c.JSON(http.StatusOK, sharedjob.GetWallet(c.Param("username")))
+	})
+	r.POST("/wallet/:username/license/:license", func(c *gin.Context) {
+		if err := sharedjob.BuyLicense(c.Param("username"), sharedjob.License(c.Param("license"))); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, sharedjob.GetWallet(c.Param("username")))
+	})
+	r.GET("/v1/cargo", func(c *gin.Context) {
+		c.JSON(http.StatusOK, sharedjob.CargoCatalogue())
+	})
+	r.GET("/v1/leaderboard", func(c *gin.Context) {
+		window := sharedjob.LeaderboardWindow(c.DefaultQuery("window", string(sharedjob.LeaderboardAllTime)))
+		entries, err := sharedjob.GetLeaderboard(window, c.Query("sort"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, entries)
+	})
 	r.GET("/fakeprogress/:station", func(c *gin.Context) {
 		stationCode := sharedjob.StationID(c.Param("station"))
 		processorCh <- sharedjob.ProgressMessage{StationID: stationCode}
 	})
 	r.POST("/job/:job_id/finish", func(c *gin.Context) {
-		userPayload := &sharedjob.UserIDPayload{}
-		if err := c.BindJSON(userPayload); err != nil {
+		finishPayload := &sharedjob.FinishJobPayload{}
+		if err := c.BindJSON(finishPayload); err != nil {
 			// make better error handling
 			panic("no valid user payload")
 		}
 
-		jobID := c.Param("job_id")
-		if ok, _, _, _, _ := sharedjob.FinishJob(userPayload.Name, jobID, processorCh); !ok {
+		ok, _, _, _, _, err := sharedjob.FinishJob(finishPayload.Name, c.Param("job_id"), finishPayload.FinishReport, processorCh)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if !ok {
 			c.Status(http.StatusBadRequest)
 			return
 		}
@@ -81,18 +183,97 @@ func main() {
 		c.Status(http.StatusOK)
 	})
 
+	r.GET("/healthz", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	r.GET("/readyz", func(c *gin.Context) {
+		if !ready.Load() {
+			c.Status(http.StatusServiceUnavailable)
+			return
+		}
+
+		c.Status(http.StatusOK)
+	})
+	r.GET("/metrics", func(c *gin.Context) {
+		c.Header("Content-Type", "text/plain; version=0.0.4")
+		sharedjob.WriteMetrics(c.Writer)
+	})
+
 	r.Any("/ws", func(c *gin.Context) {
 		sharedjob.HandleWebsocket(c.Writer, c.Request, clientCh)
 	})
 
 	ui.AddUIHandlers(r, processorCh)
 
-	tcpListener, err := net.Listen("tcp", ":8083")
+	tcpListener, err := net.Listen("tcp", cfg.Network.Listen)
 	if err != nil {
 		panic(err)
 	}
-	err = r.RunListener(tcpListener)
-	if err != nil {
-		panic(err)
+
+	srv := &http.Server{
+		Handler:           r,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout(),
+	}
+	go func() {
+		if err := srv.Serve(tcpListener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			panic(err)
+		}
+	}()
+	ready.Store(true)
+	logrus.WithField("addr", tcpListener.Addr().String()).Info("server started")
+
+	signalCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	<-signalCtx.Done()
+
+	logrus.Info("shutting down")
+	ready.Store(false)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout())
+	defer cancel()
+
+	// stop accepting new requests first so no new websocket slips in after the close frames
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logrus.WithError(err).Error("http server did not shut down cleanly")
+	}
+
+	// bots are players too and must not work through the shutdown
+	stopBots()
+	waitBots()
+
+	// websockets are hijacked connections and not covered by srv.Shutdown
+	deadline, _ := shutdownCtx.Deadline()
+	sharedjob.CloseAllConnections(deadline)
+
+	// no production may finish between saving the state and exiting
+	close(clockStop)
+	<-clockDone
+
+	if cfg.Persistence.StatePath != "" {
+		if err := sharedjob.SaveState(cfg.Persistence.StatePath); err != nil {
+			logrus.WithError(err).Error("could not save state")
+		}
+	}
+
+	logrus.Info("shutdown complete")
+}
+
+// worldClockTick is how often the wall clock is passed on to the world clock
+const worldClockTick = 10 * time.Second
+
+// runWorldClock advances the world clock until stop is closed
+func runWorldClock(progressCh chan<- sharedjob.ProgressMessage, stop <-chan struct{}) {
+	ticker := time.NewTicker(worldClockTick)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			sharedjob.AdvanceWorldClock(now.Sub(last), progressCh)
+			last = now
+		}
 	}
 }
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/devnull-twitch/sharedjob-server"
+	"github.com/sirupsen/logrus"
+)
+
+// Headless economy simulator. Builds the world in-process and lets a single simulated
+// player finish jobs based on the selected policy.
+
+const simUsername = "Simulator"
+
+type cargoStats struct {
+	jobs  int
+	cars  int
+	wages int
+}
+
+func main() {
+	seed := flag.Int64("seed", 0, "world seed (0 = random)")
+	steps := flag.Int("steps", 500, "number of job completions to simulate")
+	policyName := flag.String("policy", "random", "player policy: random, greedy or chain")
+	wageModel := flag.String("wage-model", "flat", "wage model: flat or distance")
+	supplyDemand := flag.Bool("supply-demand", false, "scale wages with supply and demand")
+	productionMinutes := flag.Int("production-minutes", 10, "world clock minutes a processor needs per batch")
+	minutesPerJob := flag.Int("minutes-per-job", 5, "world clock minutes that pass per finished job")
+	flag.Parse()
+
+	cfg := sharedjob.DefaultConfig()
+	cfg.LogLevel = "warn"
+	cfg.Economy.WageModel = *wageModel
+	cfg.Economy.SupplyDemand = *supplyDemand
+	cfg.Economy.ProductionMinutes = *productionMinutes
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	sharedjob.ApplyConfig(cfg)
+
+	usedSeed := sharedjob.SeedWorld(*seed)
+	sharedjob.Setup()
+	// the simulation looks at the economy, not at license progression
+	sharedjob.GrantLicenses(simUsername, sharedjob.AllLicenses()...)
+
+	playerPolicy, err := newPolicy(*policyName, usedSeed)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	progressCh := make(chan sharedjob.ProgressMessage)
+	go func() {
+		for range progressCh {
+		}
+	}()
+
+	perCargo := make(map[sharedjob.CargoType]*cargoStats)
+	unloadsPerStation := make(map[sharedjob.StationID]int)
+	maxBuffer := make(map[sharedjob.StationID]map[sharedjob.CargoType]int)
+	var neverFreed map[string]bool
+
+	totalWages := 0
+	finished := 0
+	waited := false
+	for finished < *steps {
+		available := make([]sharedjob.Job, 0)
+		for _, station := range sharedjob.SortedStations() {
+			for _, j := range sharedjob.GetAllStationJobs(station.ID) {
+				if !j.IsReserved() {
+					available = append(available, j)
+				}
+			}
+		}
+
+		if len(available) <= 0 {
+			// the next load jobs may still be in production
+			if !waited {
+				sharedjob.AdvanceWorldClock(cfg.Economy.ProductionDuration(), progressCh)
+				waited = true
+				continue
+			}
+
+			fmt.Printf("world stalled after %d jobs: no spawned jobs left\n\n", finished)
+			break
+		}
+		waited = false
+
+		j := playerPolicy.Pick(available)
+		if !sharedjob.ReserveJob(simUsername, j.ID) {
+			logrus.WithField("job_id", j.ID).Fatal("unable to reserve job")
+		}
+		if ok, _, _, _ := sharedjob.TakeJob(simUsername, j.ID, progressCh); !ok {
+			logrus.WithField("job_id", j.ID).Fatal("unable to take job")
+		}
+		if ok, _, _, _, _, _ := sharedjob.FinishJob(simUsername, j.ID, sharedjob.FinishReport{}, progressCh); !ok {
+			logrus.WithField("job_id", j.ID).Fatal("unable to finish job")
+		}
+
+		sharedjob.AdvanceWorldClock(time.Duration(*minutesPerJob)*time.Minute, progressCh)
+
+		playerPolicy.Finished(j)
+		finished++
+		totalWages += j.Wage
+
+		if _, ok := perCargo[j.CargoType]; !ok {
+			perCargo[j.CargoType] = &cargoStats{}
+		}
+		perCargo[j.CargoType].jobs++
+		perCargo[j.CargoType].cars += j.CarCount
+		perCargo[j.CargoType].wages += j.Wage
+
+		if j.JobType == sharedjob.ShuntingUnloadJobType {
+			unloadsPerStation[j.TargetStationName]++
+		}
+
+		neverFreed = sampleTracks(neverFreed)
+		sampleBuffers(maxBuffer)
+	}
+
+	close(progressCh)
+
+	fmt.Printf(
+		"seed %d, policy %s, wage model %s, %d jobs finished, %d wages paid\n\n",
+		usedSeed, *policyName, *wageModel, finished, totalWages,
+	)
+	printCargoStats(perCargo)
+	printStarvedStations(unloadsPerStation)
+	printOverflow(maxBuffer)
+	printNeverFreed(neverFreed)
+}
+
+// sampleTracks keeps only those tracks that have been occupied in every sample so far.
+func sampleTracks(neverFreed map[string]bool) map[string]bool {
+	occupied := make(map[string]bool)
+	for _, station := range sharedjob.SortedStations() {
+		for _, trackType := range []sharedjob.TrackTypeID{
+			sharedjob.InputTrackType,
+			sharedjob.StorageTrackType,
+			sharedjob.OutputTrackType,
+			sharedjob.LoadingTrackType,
+		} {
+			for _, trackName := range station.GetOccupiedTrackNames(trackType) {
+				occupied[trackName] = true
+			}
+		}
+	}
+
+	if neverFreed == nil {
+		return occupied
+	}
+
+	for trackName := range neverFreed {
+		if !occupied[trackName] {
+			delete(neverFreed, trackName)
+		}
+	}
+
+	return neverFreed
+}
+
+func sampleBuffers(maxBuffer map[sharedjob.StationID]map[sharedjob.CargoType]int) {
+	for _, station := range sharedjob.SortedStations() {
+		for cType, count := range station.GetCargoBuffer() {
+			if count <= 0 {
+				continue
+			}
+
+			if _, ok := maxBuffer[station.ID]; !ok {
+				maxBuffer[station.ID] = make(map[sharedjob.CargoType]int)
+			}
+
+			if count > maxBuffer[station.ID][cType] {
+				maxBuffer[station.ID][cType] = count
+			}
+		}
+	}
+}
+
+func printCargoStats(perCargo map[sharedjob.CargoType]*cargoStats) {
+	cargoTypes := make([]sharedjob.CargoType, 0, len(perCargo))
+	for cType := range perCargo {
+		cargoTypes = append(cargoTypes, cType)
+	}
+	slices.Sort(cargoTypes)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "Cargo\tJobs\tCars\tWages")
+	for _, cType := range cargoTypes {
+		stats := perCargo[cType]
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\n", cType, stats.jobs, stats.cars, stats.wages)
+	}
+	tw.Flush()
+	fmt.Println()
+}
+
+func printStarvedStations(unloadsPerStation map[sharedjob.StationID]int) {
+	fmt.Println("Starved stations (inputs but no unload job finished):")
+	for _, station := range sharedjob.SortedStations() {
+		if len(station.AllInputs()) > 0 && unloadsPerStation[station.ID] == 0 {
+			fmt.Printf("  %s (inputs: %v)\n", station.ID, station.AllInputs())
+		}
+	}
+	fmt.Println()
+}
+
+func printOverflow(maxBuffer map[sharedjob.StationID]map[sharedjob.CargoType]int) {
+	fmt.Println("Overflowed cargo buffers (max cars waiting):")
+	for _, stationID := range sharedjob.SortedStationIDs() {
+		cargoTypes := make([]sharedjob.CargoType, 0, len(maxBuffer[stationID]))
+		for cType := range maxBuffer[stationID] {
+			cargoTypes = append(cargoTypes, cType)
+		}
+		slices.Sort(cargoTypes)
+
+		for _, cType := range cargoTypes {
+			fmt.Printf("  %s %s: %d\n", stationID, cType, maxBuffer[stationID][cType])
+		}
+	}
+	fmt.Println()
+}
+
+func printNeverFreed(neverFreed map[string]bool) {
+	trackNames := make([]string, 0, len(neverFreed))
+	for trackName := range neverFreed {
+		trackNames = append(trackNames, trackName)
+	}
+	slices.Sort(trackNames)
+
+	fmt.Println("Tracks never freed:")
+	for _, trackName := range trackNames {
+		fmt.Printf("  %s\n", trackName)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/devnull-twitch/sharedjob-server"
+)
+
+// policy decides which of the currently available jobs a simulated player works on next.
+type policy interface {
+	Pick(available []sharedjob.Job) sharedjob.Job
+	Finished(j sharedjob.Job)
+}
+
+func newPolicy(name string, seed int64) (policy, error) {
+	switch name {
+	case "random":
+		return &randomPolicy{rnd: rand.New(rand.NewSource(seed))}, nil
+	case "greedy":
+		return &greedyPolicy{}, nil
+	case "chain":
+		return &chainPolicy{}, nil
+	}
+
+	return nil, fmt.Errorf("unknown policy %s", name)
+}
+
+type randomPolicy struct {
+	rnd *rand.Rand
+}
+
+func (p *randomPolicy) Pick(available []sharedjob.Job) sharedjob.Job {
+	return available[p.rnd.Intn(len(available))]
+}
+
+func (p *randomPolicy) Finished(j sharedjob.Job) {}
+
+// greedyPolicy always takes the best paying job.
+type greedyPolicy struct{}
+
+func (p *greedyPolicy) Pick(available []sharedjob.Job) sharedjob.Job {
+	best := available[0]
+	for _, j := range available[1:] {
+		if j.Wage > best.Wage {
+			best = j
+		}
+	}
+
+	return best
+}
+
+func (p *greedyPolicy) Finished(j sharedjob.Job) {}
+
+// chainPolicy follows the cargo: after finishing a job it prefers jobs starting at the
+// station the last job ended at and falls back to the best paying job otherwise.
+type chainPolicy struct {
+	lastStation sharedjob.StationID
+	greedy      greedyPolicy
+}
+
+func (p *chainPolicy) Pick(available []sharedjob.Job) sharedjob.Job {
+	local := make([]sharedjob.Job, 0)
+	for _, j := range available {
+		if j.StartingStationName == p.lastStation {
+			local = append(local, j)
+		}
+	}
+
+	if len(local) > 0 {
+		return p.greedy.Pick(local)
+	}
+
+	return p.greedy.Pick(available)
+}
+
+func (p *chainPolicy) Finished(j sharedjob.Job) {
+	p.lastStation = j.TargetStationName
+}
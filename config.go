@@ -0,0 +1,344 @@
+package sharedjob
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+type (
+	NetworkConfig struct {
+		Listen           string   `json:"listen"`
+		WSReadBuffer     int      `json:"ws_read_buffer"`
+		WSWriteBuffer    int      `json:"ws_write_buffer"`
+		WSAllowedOrigins []string `json:"ws_allowed_origins"`
+	}
+	PersistenceConfig struct {
+		StatePath      string `json:"state_path"`
+		ArchiveMaxJobs int    `json:"archive_max_jobs"`
+	}
+	EconomyConfig struct {
+		MaxCarsPerJob           int                           `json:"max_cars_per_job"`
+		CategoryWages           map[CargoCategory]int         `json:"category_wages"`
+		FreightUnloadWagePerCar int                           `json:"freight_unload_wage_per_car"`
+		WageMultiplier          float64                       `json:"wage_multiplier"`
+		WageModel               string                        `json:"wage_model"`
+		DistanceReferenceKm     float64                       `json:"distance_reference_km"`
+		SupplyDemand            bool                          `json:"supply_demand"`
+		TimeLimitMinutes        map[JobType]int               `json:"time_limit_minutes"`
+		TimeLimitMinutesPerKm   float64                       `json:"time_limit_minutes_per_km"`
+		EarlyBonus              float64                       `json:"early_bonus"`
+		LatePenalty             float64                       `json:"late_penalty"`
+		DamagePenalty           map[CargoCategory]DamageCurve `json:"damage_penalty"`
+		LicensesEnabled         bool                          `json:"licenses_enabled"`
+		LicensePrices           map[License]int               `json:"license_prices"`
+		StartingLicenses        []License                     `json:"starting_licenses"`
+		ChainBonus              float64                       `json:"chain_bonus"`
+		ProductionMinutes       int                           `json:"production_minutes"`
+		ProductionCapacity      int                           `json:"production_capacity"`
+	}
+	TimeoutConfig struct {
+		ShutdownSeconds    int `json:"shutdown_seconds"`
+		ReadHeaderSeconds  int `json:"read_header_seconds"`
+		WSHandshakeSeconds int `json:"ws_handshake_seconds"`
+	}
+	Config struct {
+		LogLevel    string            `json:"log_level"`
+		Seed        int64             `json:"seed"`
+		BotsPath    string            `json:"bots_path"`
+		Network     NetworkConfig     `json:"network"`
+		Persistence PersistenceConfig `json:"persistence"`
+		Economy     EconomyConfig     `json:"economy"`
+		Timeouts    TimeoutConfig     `json:"timeouts"`
+
+		// envErr keeps environment parse errors until Validate reports them together
+		// with every other invalid setting
+		envErr error
+	}
+)
+
+// economy holds the active economy settings. Replaced by ApplyConfig.
+var economy = DefaultConfig().Economy
+
+func DefaultConfig() Config {
+	return Config{
+		LogLevel: "info",
+		Network: NetworkConfig{
+			Listen: ":8083",
+		},
+		Persistence: PersistenceConfig{
+			ArchiveMaxJobs: 5000,
+		},
+		Economy: EconomyConfig{
+			MaxCarsPerJob: MAX_CARS_PER_JOB,
+			CategoryWages: map[CargoCategory]int{
+				CategoryRaw:     400,
+				CategoryDanger:  1000,
+				CategoryEasy:    600,
+				CategoryComplex: 800,
+			},
+			FreightUnloadWagePerCar: 500,
+			WageMultiplier:          1,
+			WageModel:               "flat",
+			DistanceReferenceKm:     5,
+			SupplyDemand:            false,
+			TimeLimitMinutes: map[JobType]int{
+				FreightJobType:        20,
+				LogisticHaulJobType:   20,
+				ShuntingLoadJobType:   30,
+				ShuntingUnloadJobType: 30,
+			},
+			TimeLimitMinutesPerKm: 2,
+			EarlyBonus:            0.2,
+			LatePenalty:           0.5,
+			DamagePenalty: map[CargoCategory]DamageCurve{
+				CategoryRaw:     {Factor: 0.5, Exponent: 1},
+				CategoryEasy:    {Factor: 1, Exponent: 1},
+				CategoryComplex: {Factor: 1.5, Exponent: 1},
+				CategoryDanger:  {Factor: 3, Exponent: 0.75},
+			},
+			LicensesEnabled: true,
+			LicensePrices: map[License]int{
+				LicenseFreightHaul:    0,
+				LicenseShunting:       5000,
+				LicenseLogisticalHaul: 10000,
+				LicenseHazmat1:        20000,
+				LicenseHazmat2:        40000,
+				LicenseHazmat3:        80000,
+				LicenseMilitary:       60000,
+				LicenseTrainLength1:   15000,
+				LicenseTrainLength2:   30000,
+			},
+			StartingLicenses: []License{LicenseFreightHaul, LicenseShunting, LicenseTrainLength1},
+			ChainBonus:       0.1,
+			// world clock minutes per batch and output cars in production per processor
+			ProductionMinutes:  10,
+			ProductionCapacity: 2 * MAX_CARS_PER_JOB,
+		},
+		Timeouts: TimeoutConfig{
+			ShutdownSeconds:    10,
+			ReadHeaderSeconds:  10,
+			WSHandshakeSeconds: 10,
+		},
+	}
+}
+
+// LoadConfig reads the config file on top of the defaults and applies environment overrides.
+// An empty path only applies the environment. Unknown keys in the file are an error. Bad
+// environment values are reported by Validate.
+func LoadConfig(path string) (Config, error) {
+	c := DefaultConfig()
+
+	if path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return c, fmt.Errorf("could not read config: %w", err)
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&c); err != nil {
+			return c, fmt.Errorf("could not parse config: %w", err)
+		}
+	}
+
+	c.envErr = c.applyEnv()
+
+	return c, nil
+}
+
+func (c *Config) applyEnv() error {
+	errs := make([]error, 0)
+
+	if v, ok := os.LookupEnv("SHAREDJOB_LOG_LEVEL"); ok {
+		c.LogLevel = v
+	}
+	if v, ok := os.LookupEnv("SHAREDJOB_SEED"); ok {
+		seed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("SHAREDJOB_SEED: %w", err))
+		}
+		c.Seed = seed
+	}
+	if v, ok := os.LookupEnv("SHAREDJOB_BOTS_PATH"); ok {
+		c.BotsPath = v
+	}
+	if v, ok := os.LookupEnv("SHAREDJOB_LISTEN"); ok {
+		c.Network.Listen = v
+	}
+	if v, ok := os.LookupEnv("SHAREDJOB_STATE_PATH"); ok {
+		c.Persistence.StatePath = v
+	}
+	if v, ok := os.LookupEnv("SHAREDJOB_WAGE_MODEL"); ok {
+		c.Economy.WageModel = v
+	}
+	if v, ok := os.LookupEnv("SHAREDJOB_SUPPLY_DEMAND"); ok {
+		supplyDemand, err := strconv.ParseBool(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("SHAREDJOB_SUPPLY_DEMAND: %w", err))
+		}
+		c.Economy.SupplyDemand = supplyDemand
+	}
+	if v, ok := os.LookupEnv("SHAREDJOB_WAGE_MULTIPLIER"); ok {
+		multiplier, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("SHAREDJOB_WAGE_MULTIPLIER: %w", err))
+		}
+		c.Economy.WageMultiplier = multiplier
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate returns an error listing every invalid field, or nil.
+func (c Config) Validate() error {
+	errs := make([]error, 0)
+	fieldErr := func(field, msg string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(msg, args...)))
+	}
+
+	if c.envErr != nil {
+		errs = append(errs, c.envErr)
+	}
+
+	if _, err := logrus.ParseLevel(c.LogLevel); err != nil {
+		fieldErr("log_level", "unknown level %q", c.LogLevel)
+	}
+
+	if c.Network.Listen == "" {
+		fieldErr("network.listen", "must not be empty")
+	}
+	if c.Network.WSReadBuffer < 0 {
+		fieldErr("network.ws_read_buffer", "must not be negative")
+	}
+	if c.Network.WSWriteBuffer < 0 {
+		fieldErr("network.ws_write_buffer", "must not be negative")
+	}
+
+	if c.Persistence.ArchiveMaxJobs < 0 {
+		fieldErr("persistence.archive_max_jobs", "must not be negative")
+	}
+
+	if c.Economy.MaxCarsPerJob < 1 {
+		fieldErr("economy.max_cars_per_job", "must be at least 1")
+	}
+	for _, category := range []CargoCategory{CategoryRaw, CategoryDanger, CategoryEasy, CategoryComplex} {
+		if wage, ok := c.Economy.CategoryWages[category]; !ok || wage < 0 {
+			fieldErr("economy.category_wages."+string(category), "must be set and not negative")
+		}
+	}
+	if c.Economy.FreightUnloadWagePerCar < 0 {
+		fieldErr("economy.freight_unload_wage_per_car", "must not be negative")
+	}
+	if c.Economy.WageMultiplier <= 0 {
+		fieldErr("economy.wage_multiplier", "must be greater than 0")
+	}
+	if _, err := NewWageCalculator(c.Economy.WageModel, c.Economy.DistanceReferenceKm); err != nil {
+		fieldErr("economy.wage_model", "must be flat or distance")
+	}
+	if c.Economy.DistanceReferenceKm <= 0 {
+		fieldErr("economy.distance_reference_km", "must be greater than 0")
+	}
+
+	for _, jobType := range []JobType{FreightJobType, LogisticHaulJobType, ShuntingLoadJobType, ShuntingUnloadJobType} {
+		if c.Economy.TimeLimitMinutes[jobType] < 1 {
+			fieldErr("economy.time_limit_minutes."+string(jobType), "must be at least 1")
+		}
+	}
+	if c.Economy.TimeLimitMinutesPerKm < 0 {
+		fieldErr("economy.time_limit_minutes_per_km", "must not be negative")
+	}
+	if c.Economy.EarlyBonus < 0 || c.Economy.EarlyBonus > 1 {
+		fieldErr("economy.early_bonus", "must be between 0 and 1")
+	}
+	if c.Economy.LatePenalty < 0 || c.Economy.LatePenalty > 1 {
+		fieldErr("economy.late_penalty", "must be between 0 and 1")
+	}
+	for _, category := range []CargoCategory{CategoryRaw, CategoryDanger, CategoryEasy, CategoryComplex} {
+		curve, ok := c.Economy.DamagePenalty[category]
+		if !ok || curve.Factor < 0 || curve.Exponent <= 0 {
+			fieldErr("economy.damage_penalty."+string(category), "must be set with a factor not negative and an exponent greater than 0")
+		}
+	}
+
+	if c.Economy.ChainBonus < 0 {
+		fieldErr("economy.chain_bonus", "must not be negative")
+	}
+	if c.Economy.ProductionMinutes < 0 {
+		fieldErr("economy.production_minutes", "must not be negative")
+	}
+	if c.Economy.ProductionCapacity < 1 {
+		fieldErr("economy.production_capacity", "must be at least 1")
+	}
+	for _, license := range AllLicenses() {
+		if price, ok := c.Economy.LicensePrices[license]; !ok || price < 0 {
+			fieldErr("economy.license_prices."+string(license), "must be set and not negative")
+		}
+	}
+	for _, license := range c.Economy.StartingLicenses {
+		if !slices.Contains(AllLicenses(), license) {
+			fieldErr("economy.starting_licenses", "unknown license %s", license)
+		}
+	}
+
+	if c.Timeouts.ShutdownSeconds < 1 {
+		fieldErr("timeouts.shutdown_seconds", "must be at least 1")
+	}
+	if c.Timeouts.ReadHeaderSeconds < 1 {
+		fieldErr("timeouts.read_header_seconds", "must be at least 1")
+	}
+	if c.Timeouts.WSHandshakeSeconds < 1 {
+		fieldErr("timeouts.ws_handshake_seconds", "must be at least 1")
+	}
+
+	return errors.Join(errs...)
+}
+
+// ApplyConfig activates log level, economy and websocket settings. Call after Validate
+// and before Setup.
+func ApplyConfig(c Config) {
+	level, _ := logrus.ParseLevel(c.LogLevel)
+	logrus.SetLevel(level)
+
+	economy = c.Economy
+	archiveMaxJobs = c.Persistence.ArchiveMaxJobs
+	wageCalculator, _ = NewWageCalculator(c.Economy.WageModel, c.Economy.DistanceReferenceKm)
+	if c.Economy.SupplyDemand {
+		wageCalculator = SupplyDemandWageCalculator{Base: wageCalculator}
+	}
+
+	upgrader = websocket.Upgrader{
+		ReadBufferSize:   c.Network.WSReadBuffer,
+		WriteBufferSize:  c.Network.WSWriteBuffer,
+		HandshakeTimeout: time.Duration(c.Timeouts.WSHandshakeSeconds) * time.Second,
+	}
+	if len(c.Network.WSAllowedOrigins) > 0 {
+		allowed := slices.Clone(c.Network.WSAllowedOrigins)
+		upgrader.CheckOrigin = func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			// game clients do not send an origin at all
+			return origin == "" || slices.Contains(allowed, origin)
+		}
+	}
+}
+
+func (c Config) ShutdownTimeout() time.Duration {
+	return time.Duration(c.Timeouts.ShutdownSeconds) * time.Second
+}
+
+func (e EconomyConfig) ProductionDuration() time.Duration {
+	return time.Duration(e.ProductionMinutes) * time.Minute
+}
+
+func (c Config) ReadHeaderTimeout() time.Duration {
+	return time.Duration(c.Timeouts.ReadHeaderSeconds) * time.Second
+}
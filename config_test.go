@@ -0,0 +1,34 @@
+package sharedjob
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigRejectsUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"economy": {"wage_modle": "distance"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadConfig(path); err == nil || !strings.Contains(err.Error(), "wage_modle") {
+		t.Errorf("expected an error naming the unknown key, got %v", err)
+	}
+}
+
+func TestValidateReportsEnvAndFieldErrors(t *testing.T) {
+	t.Setenv("SHAREDJOB_SEED", "not-a-number")
+	t.Setenv("SHAREDJOB_WAGE_MODEL", "nope")
+
+	c, err := LoadConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.Validate()
+	if err == nil || !strings.Contains(err.Error(), "SHAREDJOB_SEED") || !strings.Contains(err.Error(), "economy.wage_model") {
+		t.Errorf("expected env and field errors together, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package sharedjob
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+type (
+	// FinishReport is what the client reports about the delivered cars. All values are
+	// percentages between 0 and 100. Cars missing from CarDamage count as undamaged.
+	FinishReport struct {
+		CarDamage []float64 `json:"car_damage,omitempty"`
+		CargoLoss float64   `json:"cargo_loss,omitempty"`
+	}
+	// DamageCurve maps the damage share to the share of the wage that is withheld:
+	// min(1, Factor * damage^Exponent). Exponents below 1 punish small damage harder.
+	DamageCurve struct {
+		Factor   float64 `json:"factor"`
+		Exponent float64 `json:"exponent"`
+	}
+)
+
+func (r FinishReport) Validate(carCount int) error {
+	errs := make([]error, 0)
+	if len(r.CarDamage) > carCount {
+		errs = append(errs, fmt.Errorf("car_damage: got %d cars, job has %d", len(r.CarDamage), carCount))
+	}
+	for i, damage := range r.CarDamage {
+		if damage < 0 || damage > 100 {
+			errs = append(errs, fmt.Errorf("car_damage[%d]: must be between 0 and 100", i))
+		}
+	}
+	if r.CargoLoss < 0 || r.CargoLoss > 100 {
+		errs = append(errs, errors.New("cargo_loss: must be between 0 and 100"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// damageShare is the average car damage or the cargo loss, whichever is worse, as 0 to 1
+func (r FinishReport) damageShare(carCount int) float64 {
+	total := 0.0
+	for _, damage := range r.CarDamage {
+		total += clampPercent(damage)
+	}
+
+	carDamage := 0.0
+	if cars := max(carCount, len(r.CarDamage)); cars > 0 {
+		carDamage = total / float64(cars)
+	}
+
+	return math.Max(carDamage, clampPercent(r.CargoLoss)) / 100
+}
+
+func (c DamageCurve) penaltyShare(damage float64) float64 {
+	if damage <= 0 {
+		return 0
+	}
+
+	return math.Min(1, c.Factor*math.Pow(damage, c.Exponent))
+}
+
+// damagePenalty returns the amount withheld from the wage for the reported damage
+func (j *Job) damagePenalty(report FinishReport) (int, float64) {
+	damage := report.damageShare(j.CarCount)
+	curve := economy.DamagePenalty[j.CargoType.Category()]
+	return int(float64(j.Wage) * curve.penaltyShare(damage)), damage
+}
+
+func clampPercent(v float64) float64 {
+	return math.Min(100, math.Max(0, v))
+}
@@ -0,0 +1,44 @@
+package sharedjob
+
+import "testing"
+
+func TestDamagePenaltyHitsDangerCargoHarder(t *testing.T) {
+	report := FinishReport{CarDamage: []float64{20, 0}}
+
+	raw := &Job{Wage: 1000, CarCount: 2, CargoType: Coal}
+	danger := &Job{Wage: 1000, CarCount: 2, CargoType: CrudeOil}
+
+	rawPenalty, damage := raw.damagePenalty(report)
+	if damage != 0.1 {
+		t.Errorf("expected 10%% damage, got %.2f", damage)
+	}
+	if dangerPenalty, _ := danger.damagePenalty(report); dangerPenalty <= rawPenalty {
+		t.Errorf("expected danger penalty %d to exceed raw penalty %d", dangerPenalty, rawPenalty)
+	}
+	if penalty, _ := raw.damagePenalty(FinishReport{}); penalty != 0 {
+		t.Errorf("expected no penalty without damage, got %d", penalty)
+	}
+	if err := (FinishReport{CargoLoss: 120}).Validate(2); err == nil {
+		t.Error("expected cargo loss above 100 to be rejected")
+	}
+}
+
+func TestFinishJobRejectsInvalidReport(t *testing.T) {
+	ResetWorld()
+	SeedWorld(5)
+	Setup()
+	GrantLicenses("test", AllLicenses()...)
+
+	progressCh := make(chan ProgressMessage, 100)
+	j := GetAllStationJobs(StationCM)[0]
+	ReserveJob("test", j.ID)
+	TakeJob("test", j.ID, progressCh)
+
+	tooManyCars := FinishReport{CarDamage: make([]float64, j.CarCount+1)}
+	if ok, _, _, _, _, err := FinishJob("test", j.ID, tooManyCars, progressCh); ok || err == nil {
+		t.Fatalf("expected a report for more cars than the job has to be rejected, got ok %t err %v", ok, err)
+	}
+	if ok, _, _, _, _, err := FinishJob("test", j.ID, FinishReport{}, progressCh); !ok || err != nil {
+		t.Errorf("expected the job to stay active after the rejected report, got ok %t err %v", ok, err)
+	}
+}
@@ -0,0 +1,83 @@
+package sharedjob
+
+import "math"
+
+const (
+	minDemandMultiplier = 0.5
+	maxDemandMultiplier = 1.5
+)
+
+// demandMultiplier rates how badly the target station needs the cargo. Processors at the
+// target running short of this cargo push the multiplier up. Cargo piling up in the target processors or in the cargo buffer of the start station
+// pulls it down. A full job worth of shortage or backlog moves it by 0.5. See inputBalance.
+func demandMultiplier(cargo CargoType, start, target StationID) float64 {
+	shortage, surplus := 0.0, 0.0
+
+	if targetStation, ok := AllStations[target]; ok {
+		for _, proc := range targetStation.Processor {
+			if !proc.isAllowed(cargo) {
+				continue
+			}
+
+			procShortage, procSurplus := proc.inputBalance(cargo)
+			shortage = math.Max(shortage, procShortage)
+			surplus = math.Max(surplus, procSurplus)
+		}
+	}
+	if startStation, ok := AllStations[start]; ok {
+		backlog := float64(startStation.cargoBuffer[cargo]) / float64(economy.MaxCarsPerJob)
+		surplus = math.Max(surplus, backlog)
+	}
+
+	multiplier := 1 + 0.5*math.Min(shortage, 1) - 0.5*math.Min(surplus, 1)
+	multiplier = math.Min(maxDemandMultiplier, math.Max(minDemandMultiplier, multiplier))
+	return math.Round(multiplier*100) / 100
+}
+
+// inputBalance rates the stock of one input in jobs worth of cars. The stock is the buffered
+// cars plus the input already turned into pending production. Less than a job worth is a
+// shortage, more than the production capacity can take a surplus. With several inputs an
+// input lagging behind the best stocked other one counts as shortage too, one ahead as
+// surplus. Both results are never negative. Sinks use up anything and never rate an input.
+func (proc *StationProcessor) inputBalance(cargo CargoType) (shortage, surplus float64) {
+	if proc.isSink() {
+		return 0, 0
+	}
+
+	jobSize := float64(economy.MaxCarsPerJob)
+	stock := float64(proc.stock(cargo))
+	shortage = math.Max(0, jobSize-stock) / jobSize
+	capacity := float64(economy.ProductionCapacity * proc.blueprint[cargo])
+	surplus = math.Max(0, stock-capacity) / jobSize
+
+	if len(proc.allowedInput) < 2 {
+		return shortage, surplus
+	}
+
+	// fill levels in number of outputs the stocked cars are good for
+	fill := stock / float64(proc.blueprint[cargo])
+	bestOther := 0.0
+	for _, other := range proc.allowedInput {
+		if other == cargo {
+			continue
+		}
+		bestOther = math.Max(bestOther, float64(proc.stock(other))/float64(proc.blueprint[other]))
+	}
+
+	diff := (bestOther - fill) * float64(proc.blueprint[cargo]) / jobSize
+	if diff > 0 {
+		return math.Max(shortage, diff), surplus
+	}
+
+	return shortage, math.Max(surplus, -diff)
+}
+
+// stock is the buffered input plus the input consumed by pending production
+func (proc *StationProcessor) stock(cargo CargoType) int {
+	stock := proc.buffer[cargo]
+	for _, batch := range proc.production {
+		stock += batch.CarCount * proc.blueprint[cargo]
+	}
+
+	return stock
+}
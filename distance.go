@@ -0,0 +1,47 @@
+package sharedjob
+
+import "math"
+
+// Approximate station positions on the map in km. Rail routes are never straight so
+// distances get stretched by routeFactor.
+var stationPositions = map[StationID][2]float64{
+	StationHB:  {2.5, 3.0},
+	StationHMB: {1.5, 1.5},
+	StationCSW: {5.0, 4.0},
+	StationMF:  {7.0, 5.0},
+	StationIMW: {10.5, 3.0},
+	StationIME: {14.0, 4.0},
+	StationSM:  {12.0, 6.5},
+	StationGF:  {9.0, 8.0},
+	StationFF:  {4.5, 9.0},
+	StationFRS: {10.0, 9.5},
+	StationFM:  {2.5, 11.5},
+	StationFRC: {8.0, 11.0},
+	StationCM:  {13.0, 11.0},
+	StationSW:  {7.0, 13.0},
+	StationOWC: {12.0, 13.0},
+	StationOWN: {14.0, 15.0},
+	StationMB:  {15.0, 15.5},
+}
+
+const routeFactor = 1.3
+
+// StationDistances holds the route length in km between every pair of stations.
+var StationDistances = newDistanceMatrix()
+
+func newDistanceMatrix() map[StationID]map[StationID]float64 {
+	matrix := make(map[StationID]map[StationID]float64, len(stationPositions))
+	for from, fromPos := range stationPositions {
+		matrix[from] = make(map[StationID]float64, len(stationPositions))
+		for to, toPos := range stationPositions {
+			dist := math.Hypot(fromPos[0]-toPos[0], fromPos[1]-toPos[1]) * routeFactor
+			matrix[from][to] = math.Round(dist*10) / 10
+		}
+	}
+
+	return matrix
+}
+
+func StationDistance(from, to StationID) float64 {
+	return StationDistances[from][to]
+}
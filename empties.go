@@ -0,0 +1,157 @@
+package sharedjob
+
+import (
+	"math"
+	"slices"
+)
+
+// emptyCargoByCarType names the owner of each car type. Empty cars travel as this cargo
+// when they are sent back to a station that loads into them.
+var emptyCargoByCarType = map[CarType]CargoType{
+	CarTankOil:         EmptySunOmni,
+	CarFlatbed:         EmptyIskar,
+	CarStock:           EmptyObco,
+	CarTankFood:        EmptyObco,
+	CarHopper:          EmptyGoorsk,
+	CarGondola:         EmptyGoorsk,
+	CarBoxcar:          EmptyKrugmann,
+	CarFlatbedStakes:   EmptyBrohm,
+	CarBoxcarMilitary:  EmptyAAG,
+	CarFlatbedMilitary: EmptyAAG,
+	CarNuclearFlask:    EmptySperex,
+	CarAutorack:        EmptyNovae,
+	CarRefrigerator:    EmptyTraeg,
+	CarTankChem:        EmptyChemlek,
+	CarTankGas:         EmptyNeoGamma,
+}
+
+func (c CargoType) IsEmpty() bool {
+	for _, emptyCargo := range emptyCargoByCarType {
+		if emptyCargo == c {
+			return true
+		}
+	}
+
+	return false
+}
+
+// takeEmptyCars removes up to count empty cars that can carry the cargo from the station
+func (s *LogicStation) takeEmptyCars(cargo CargoType, count int) []Car {
+	taken := reusableCars(s.emptyCars, cargo, count)
+	s.emptyCars = slices.DeleteFunc(s.emptyCars, func(car Car) bool {
+		return slices.ContainsFunc(taken, func(takenCar Car) bool { return takenCar.ID == car.ID })
+	})
+
+	return taken
+}
+
+// takeEmptyCarType removes up to count empty cars of the car type from the station
+func (s *LogicStation) takeEmptyCarType(carType CarType, count int) []Car {
+	taken := make([]Car, 0, count)
+	s.emptyCars = slices.DeleteFunc(s.emptyCars, func(car Car) bool {
+		if car.Type != carType || len(taken) >= count {
+			return false
+		}
+		taken = append(taken, car)
+		return true
+	})
+
+	return taken
+}
+
+// needsCarType reports whether any processor of the station loads its output into the car type
+func (s *LogicStation) needsCarType(carType CarType) bool {
+	return s.emptyCarsNeeded(carType) > 0
+}
+
+// emptyCarsNeeded is the number of empties of the car type the station keeps for its own
+// output, the largest load job for each processor loading into it.
+func (s *LogicStation) emptyCarsNeeded(carType CarType) int {
+	needed := 0
+	for _, proc := range s.Processor {
+		if proc.output != None && slices.Contains(cargoCatalogue[proc.output].CarTypes, carType) {
+			needed += s.cargoLoadMaxCount
+		}
+	}
+
+	return needed
+}
+
+func (s *LogicStation) countEmptyCars(carType CarType) int {
+	count := 0
+	for _, car := range s.emptyCars {
+		if car.Type == carType {
+			count++
+		}
+	}
+
+	return count
+}
+
+// GetEmptyCars returns the number of empty cars waiting at the station per car type
+func (s *LogicStation) GetEmptyCars() map[CarType]int {
+	counts := make(map[CarType]int)
+	for _, car := range s.emptyCars {
+		counts[car.Type]++
+	}
+
+	return counts
+}
+
+// dispatchEmptyCars creates logistic haul jobs that return empties the station has no use
+// for to the station that needs them most. Stations loading into the car type keep what their
+// output needs and send the rest on. Nothing moves until a job worth of cars is left over.
+func (s *LogicStation) dispatchEmptyCars() []*Job {
+	carTypes := make([]CarType, 0)
+	for _, car := range s.emptyCars {
+		if !slices.Contains(carTypes, car.Type) {
+			carTypes = append(carTypes, car.Type)
+		}
+	}
+	slices.Sort(carTypes)
+
+	newJobs := make([]*Job, 0)
+	for _, carType := range carTypes {
+		surplus := s.countEmptyCars(carType) - s.emptyCarsNeeded(carType)
+		if surplus < s.cargoLoadMinCount {
+			continue
+		}
+
+		target := s.emptyCarTarget(carType)
+		if target == nil {
+			continue
+		}
+
+		cargo := emptyCargoByCarType[carType]
+		for surplus > 0 {
+			cars := s.takeEmptyCarType(carType, min(surplus, economy.MaxCarsPerJob))
+			surplus -= len(cars)
+			newJobs = append(newJobs, s.addJob(target.ID, LogisticHaulJobType, len(cars), cargo, &jobOrigin{cars: cars}, "return empty cars"))
+		}
+	}
+
+	return newJobs
+}
+
+// emptyCarTarget picks the station with the fewest empties of the car type among those
+// loading into it, the closest one on a tie.
+func (s *LogicStation) emptyCarTarget(carType CarType) *LogicStation {
+	var (
+		best         *LogicStation
+		bestEmpties  = math.MaxInt
+		bestDistance = math.MaxFloat64
+	)
+	for _, candidate := range SortedStations() {
+		if candidate == s || !candidate.needsCarType(carType) {
+			continue
+		}
+
+		empties := candidate.countEmptyCars(carType)
+		distance := StationDistance(s.ID, candidate.ID)
+		if empties < bestEmpties || (empties == bestEmpties && distance < bestDistance) {
+			best, bestEmpties, bestDistance = candidate, empties, distance
+		}
+	}
+
+	return best
+}
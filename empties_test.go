@@ -0,0 +1,101 @@
+package sharedjob
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEmptyCarsReturnAndGetLoaded(t *testing.T) {
+	ResetWorld()
+	SeedWorld(9)
+	Setup()
+
+	sm := AllStations[StationSM]
+	for i := 0; i < sm.cargoLoadMinCount; i++ {
+		sm.emptyCars = append(sm.emptyCars, newCar(IronOre))
+	}
+	returned := sm.emptyCars[0]
+
+	returnJobs := sm.dispatchEmptyCars()
+	if len(returnJobs) != 1 || returnJobs[0].JobType != LogisticHaulJobType || returnJobs[0].CargoType != EmptyGoorsk {
+		t.Fatalf("expected one EmptyGoorsk return job, got %+v", returnJobs)
+	}
+	if len(sm.emptyCars) != 0 {
+		t.Errorf("expected empties to leave SM, %d left", len(sm.emptyCars))
+	}
+
+	target := AllStations[returnJobs[0].TargetStationName]
+	target.ProcessJob(returnJobs[0])
+	for _, proc := range target.Processor {
+		if proc.output == None || !slices.Contains(cargoCatalogue[proc.output].CarTypes, returned.Type) {
+			continue
+		}
+
+		loadJob := target.spawnGenerativeLoadJob(proc)
+		if loadJob.Cars[0] != returned {
+			t.Errorf("expected load job to start with returned car %v, got %v", returned, loadJob.Cars[0])
+		}
+		return
+	}
+	t.Fatalf("target %s does not load into %s", target.ID, returned.Type)
+}
+
+func TestStationKeepsOnlyTheEmptiesItLoads(t *testing.T) {
+	ResetWorld()
+	SeedWorld(9)
+	Setup()
+
+	hb := AllStations[StationHB]
+	kept := hb.emptyCarsNeeded(CarTankOil)
+	if kept == 0 {
+		t.Fatalf("expected HB to load into %s", CarTankOil)
+	}
+	for i := 0; i < kept+hb.cargoLoadMinCount; i++ {
+		hb.emptyCars = append(hb.emptyCars, newCar(CrudeOil))
+	}
+
+	returnJobs := hb.dispatchEmptyCars()
+	sent := 0
+	for _, j := range returnJobs {
+		if j.CargoType != EmptySunOmni || j.TargetStationName == StationHB {
+			t.Errorf("expected EmptySunOmni sent away from HB, got %s to %s", j.CargoType, j.TargetStationName)
+		}
+		sent += j.CarCount
+	}
+	if sent != hb.cargoLoadMinCount {
+		t.Errorf("expected %d surplus tank cars sent on, got %d", hb.cargoLoadMinCount, sent)
+	}
+	if got := hb.countEmptyCars(CarTankOil); got != kept {
+		t.Errorf("expected HB to keep %d tank cars, kept %d", kept, got)
+	}
+}
+
+func TestMergedOutputMakesNoNewCars(t *testing.T) {
+	ResetWorld()
+	SeedWorld(9)
+	Setup()
+
+	cm := AllStations[StationCM]
+	cm.JobQueue = nil
+	waiting := cm.AddJob(StationCM, ShuntingLoadJobType, economy.MaxCarsPerJob-2, Coal, "test")
+	delivered := make([]Car, 0, 5)
+	for i := 0; i < 5; i++ {
+		delivered = append(delivered, newCar(Coal))
+	}
+	carsMade := lastCarNum
+
+	if newJob := cm.addCargo(Coal, len(delivered), &jobOrigin{cars: delivered}); newJob != nil {
+		t.Fatalf("expected the output to merge into %s, got new job %s", waiting.ID, newJob.ID)
+	}
+	if lastCarNum != carsMade {
+		t.Errorf("expected no new cars for merged output, %d made", lastCarNum-carsMade)
+	}
+	if !slices.Contains(waiting.Cars, delivered[1]) || len(waiting.Cars) != economy.MaxCarsPerJob {
+		t.Errorf("expected the waiting job to grow with the delivered cars, got %v", waiting.Cars)
+	}
+
+	buffered := cm.takeBufferedCars(Coal, economy.MaxCarsPerJob)
+	if len(buffered) != 3 || cm.cargoBuffer[Coal] != 3 || buffered[0] != delivered[2] {
+		t.Errorf("expected the 3 overflowing cars in the buffer, got %v for %d cars", buffered, cm.cargoBuffer[Coal])
+	}
+}
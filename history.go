@@ -0,0 +1,113 @@
+package sharedjob
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+type (
+	JobEventType string
+	JobEvent     struct {
+		Time   time.Time    `json:"time"`
+		Type   JobEventType `json:"type"`
+		User   string       `json:"user,omitempty"`
+		Reason string       `json:"reason,omitempty"`
+	}
+)
+
+const (
+	JobEventCreated        JobEventType = "created"
+	JobEventSpawned        JobEventType = "spawned"
+	JobEventTrackChanged   JobEventType = "track_changed"
+	JobEventUnspawned      JobEventType = "unspawned"
+	JobEventCargoAdded     JobEventType = "cargo_added"
+	JobEventReserved       JobEventType = "reserved"
+	JobEventUnreserved     JobEventType = "unreserved"
+	JobEventTaken          JobEventType = "taken"
+	JobEventFinished       JobEventType = "finished"
+	JobEventDamaged        JobEventType = "damaged"
+	JobEventChainCompleted JobEventType = "chain_completed"
+)
+
+// Finished jobs are kept around so their history can still be looked up. Only the newest
+// archiveMaxJobs are kept, 0 keeps all of them. Guarded by jobLock.
+var (
+	archivedJobs    = []*Job{}
+	archivedByID    = map[string]*Job{}
+	archivedByChain = map[string][]*Job{}
+	archiveMaxJobs  = DefaultConfig().Persistence.ArchiveMaxJobs
+)
+
+func (t JobEventType) String() string {
+	return string(t)
+}
+
+func (j *Job) addHistory(eventType JobEventType, user, reason string) {
+	j.history = append(j.history, JobEvent{
+		Time:   time.Now(),
+		Type:   eventType,
+		User:   user,
+		Reason: reason,
+	})
+}
+
+func (j *Job) GetHistory() []JobEvent {
+	return slices.Clone(j.history)
+}
+
+// snapshot copies the job including its slices so it can be read without holding jobLock
+func (j *Job) snapshot() Job {
+	c := *j
+	c.Cars = slices.Clone(j.Cars)
+	c.LicenseRequired = slices.Clone(j.LicenseRequired)
+	c.history = slices.Clone(j.history)
+	return c
+}
+
+func resetArchive() {
+	archivedJobs = []*Job{}
+	archivedByID = map[string]*Job{}
+	archivedByChain = map[string][]*Job{}
+}
+
+// archiveJob adds a finished job and drops the oldest ones beyond the limit
+func archiveJob(j *Job) {
+	archivedJobs = append(archivedJobs, j)
+	archivedByID[j.ID] = j
+	archivedByChain[j.ChainID] = append(archivedByChain[j.ChainID], j)
+
+	for archiveMaxJobs > 0 && len(archivedJobs) > archiveMaxJobs {
+		dropped := archivedJobs[0]
+		archivedJobs = archivedJobs[1:]
+		delete(archivedByID, dropped.ID)
+
+		chainJobs := slices.DeleteFunc(archivedByChain[dropped.ChainID], func(chainJob *Job) bool {
+			return chainJob == dropped
+		})
+		if len(chainJobs) > 0 {
+			archivedByChain[dropped.ChainID] = chainJobs
+		} else {
+			delete(archivedByChain, dropped.ChainID)
+		}
+	}
+}
+
+// FindJob looks up a job in all station queues and the archive of finished jobs. Returns a
+// copy that stays valid after the job changes.
+func FindJob(jobID string) (Job, error) {
+	jobLock.Lock()
+	defer jobLock.Unlock()
+
+	for _, logicStation := range SortedStations() {
+		if j, err := logicStation.GetJob(jobID); err == nil {
+			return j.snapshot(), nil
+		}
+	}
+
+	if j, ok := archivedByID[jobID]; ok {
+		return j.snapshot(), nil
+	}
+
+	return Job{}, fmt.Errorf("job %s not found", jobID)
+}
@@ -1,8 +1,10 @@
 package sharedjob
 
 import (
+	"fmt"
 	"slices"
 	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
@@ -15,16 +17,27 @@ type (
 		StartingStationName StationID `json:"-"`
 		StartingTrack       string    `json:"starting_track"`
 		startTrackType      TrackTypeID
+		fixedStartTrack     bool
+		LoadingTrack        string    `json:"loading_track"`
 		TargetStationName   StationID `json:"target_station"`
 		TargetTrack         string    `json:"target_track"`
 		targetTrackType     TrackTypeID
-		CarCount            int       `json:"car_count"`
-		CargoType           CargoType `json:"cargo_type"`
-		Wage                int       `json:"wage"`
+		CarCount            int        `json:"car_count"`
+		Cars                []Car      `json:"cars"`
+		CargoType           CargoType  `json:"cargo_type"`
+		ChainID             string     `json:"chain_id"`
+		Wage                int        `json:"wage"`
+		WageMultiplier      float64    `json:"wage_multiplier"`
+		Deadline            *time.Time `json:"deadline,omitempty"`
+		Payout              int        `json:"payout"`
+		LicenseRequired     []License  `json:"license_required"`
 		jobReserved         bool
 		jobActive           bool
 		jobAssignedUser     string
 		jobSpawned          bool
+		reservedAt          time.Time
+		takenAt             time.Time
+		history             []JobEvent
 	}
 )
 
@@ -121,12 +134,43 @@ func ReserveJob(userName string, jobID string) bool {
 				if j.jobReserved || j.jobActive {
 					return false
 				}
+				if missing := j.missingLicenses(userName); len(missing) > 0 {
+					logrus.WithFields(logrus.Fields{"job_id": jobID, "user": userName, "missing": missing}).Info("license missing")
+					return false
+				}
 
 				jobLock.Lock()
 				defer jobLock.Unlock()
 
 				logicStation.JobQueue[index].jobReserved = true
 				logicStation.JobQueue[index].jobAssignedUser = userName
+				logicStation.JobQueue[index].reservedAt = time.Now()
+				logicStation.JobQueue[index].addHistory(JobEventReserved, userName, "")
+
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// UnreserveJob hands a reserved but not yet taken job back to everyone.
+func UnreserveJob(userName string, jobID string) bool {
+	jobLock.Lock()
+	defer jobLock.Unlock()
+
+	for _, logicStation := range AllStations {
+		for _, j := range logicStation.JobQueue {
+			if j.ID == jobID {
+				if !j.jobReserved || j.jobActive || j.jobAssignedUser != userName {
+					return false
+				}
+
+				j.jobReserved = false
+				j.jobAssignedUser = ""
+				j.reservedAt = time.Time{}
+				j.addHistory(JobEventUnreserved, userName, "")
 
 				return true
 			}
@@ -148,8 +192,17 @@ func TakeJob(userName string, jobID string, progressCh chan<- ProgressMessage) (
 				if !j.jobReserved || j.jobActive || j.jobAssignedUser != userName {
 					return false, nil, nil, nil
 				}
+				if missing := j.missingLicenses(userName); len(missing) > 0 {
+					logrus.WithFields(logrus.Fields{"job_id": jobID, "user": userName, "missing": missing}).Info("license missing")
+					return false, nil, nil, nil
+				}
 
 				logicStation.JobQueue[index].jobActive = true
+				logicStation.JobQueue[index].takenAt = time.Now()
+				deadline := j.takenAt.Add(j.timeLimit())
+				logicStation.JobQueue[index].Deadline = &deadline
+				reserveToTakeSeconds.observe(j.takenAt.Sub(j.reservedAt))
+				j.addHistory(JobEventTaken, userName, "")
 
 				updateData := updateAllJobs(j)
 				if !slices.ContainsFunc(updateData.changedJobs, func(checkJob *Job) bool {
@@ -160,7 +213,7 @@ func TakeJob(userName string, jobID string, progressCh chan<- ProgressMessage) (
 
 				for _, sid := range updateData.notifyStationIDs {
 					logrus.WithField("station_id", sid).Info("notifying station after job completion impact")
-					progressCh <- ProgressMessage{StationID: sid}
+					progressCh <- ProgressMessage{StationID: sid, JobID: j.ID, Event: JobEventTaken}
 				}
 
 				return true, updateData.unspawnJobs, updateData.spawnJobs, updateData.changedJobs
@@ -168,48 +221,80 @@ func TakeJob(userName string, jobID string, progressCh chan<- ProgressMessage) (
 		}
 	}
 
-	jobLock.Unlock()
 	return false, nil, nil, nil
 }
 
-func FinishJob(userName string, jobID string, progressCh chan<- ProgressMessage) (bool, []*Job, []*Job, []*Job, []*Job) {
+// FinishJob completes an active job of the user. The report is validated against the job
+// under the job lock, an invalid report is returned as error and leaves the job active.
+func FinishJob(userName string, jobID string, report FinishReport, progressCh chan<- ProgressMessage) (bool, []*Job, []*Job, []*Job, []*Job, error) {
+	jobLock.Lock()
+	defer func() {
+		jobLock.Unlock()
+	}()
+
 	for _, logicStation := range AllStations {
 		for index, j := range logicStation.JobQueue {
 			if j.ID == jobID {
 				if !j.jobActive || j.jobAssignedUser != userName {
-					return false, nil, nil, nil, nil
+					return false, nil, nil, nil, nil, nil
+				}
+				if err := report.Validate(j.CarCount); err != nil {
+					return false, nil, nil, nil, nil, err
 				}
 
-				jobLock.Lock()
-				defer func() {
-					jobLock.Unlock()
-				}()
+				finishedAt := time.Now()
+				takeToFinishSeconds.observe(finishedAt.Sub(j.takenAt))
 
 				filtered := make([]*Job, 0, len(logicStation.JobQueue))
 				filtered = append(filtered, logicStation.JobQueue[:index]...)
 				filtered = append(filtered, logicStation.JobQueue[index+1:]...)
 				logicStation.JobQueue = filtered
 
+				adjustment, timing := j.deliveryAdjustment(finishedAt)
+				damagePenalty, damage := j.damagePenalty(report)
+				j.Payout = j.Wage + adjustment - damagePenalty
+
+				if damage > 0 {
+					j.addHistory(JobEventDamaged, userName, fmt.Sprintf("%.1f%% damage, %d withheld", damage*100, damagePenalty))
+				}
+				j.addHistory(JobEventFinished, userName, timing)
+				archiveJob(j)
+				book(userName, j.ID, j.Wage, "job finished")
+				switch {
+				case adjustment > 0:
+					book(userName, j.ID, adjustment, "early delivery bonus")
+				case adjustment < 0:
+					book(userName, j.ID, adjustment, "late delivery penalty")
+				}
+				if damagePenalty > 0 {
+					book(userName, j.ID, -damagePenalty, "cargo damage penalty")
+				}
+				if chainBonus := soloChainBonus(j.ChainID, userName); chainBonus > 0 {
+					j.Payout += chainBonus
+					j.addHistory(JobEventChainCompleted, userName, fmt.Sprintf("every leg of chain %s done solo", j.ChainID))
+					book(userName, j.ID, chainBonus, "chain completion bonus")
+				}
+				recordLeaderboard(j)
+
 				newlyCreatedJobs := GetStation(j.TargetStationName).ProcessJob(j)
 
 				updateData := updateAllJobs(j)
 
-				// fot now unspawn the finished job and spawn follow up as fresh job.
-				// would be sweet if we would keep the cars
-				// ( aka make use of proper jobChains in game logic, but hey ...  )
+				// the finished job goes away, its cars live on in the follow-up job with the
+				// same chain_id and car IDs
 				updateData.unspawnJobs = slices.Insert(updateData.unspawnJobs, 0, j)
 
 				for _, sid := range updateData.notifyStationIDs {
 					logrus.WithField("station_id", sid).Info("notifying station after job completion impact")
-					progressCh <- ProgressMessage{StationID: sid}
+					progressCh <- ProgressMessage{StationID: sid, JobID: j.ID, Event: JobEventFinished}
 				}
 
-				return true, updateData.unspawnJobs, updateData.spawnJobs, updateData.changedJobs, newlyCreatedJobs
+				return true, updateData.unspawnJobs, updateData.spawnJobs, updateData.changedJobs, newlyCreatedJobs, nil
 			}
 		}
 	}
 
-	return false, nil, nil, nil, nil
+	return false, nil, nil, nil, nil, nil
 }
 
 type updateAllPayload struct {
@@ -227,8 +312,11 @@ func updateAllJobs(srcJob *Job) updateAllPayload {
 		changedJobs:      make([]*Job, 0),
 	}
 
+	rebalanceStorage()
+
 	changeFlagPerStation := make(map[StationID]bool)
-	for stationID, logicStation := range AllStations {
+	for _, logicStation := range SortedStations() {
+		stationID := logicStation.ID
 		changed, stationDeleteIds, stationNewJobs, stationChangedJobs := logicStation.ValidateJobs(ShuntingUnloadJobType)
 		changeFlagPerStation[stationID] = changed
 
@@ -236,7 +324,8 @@ func updateAllJobs(srcJob *Job) updateAllPayload {
 		retVal.spawnJobs = append(retVal.spawnJobs, stationNewJobs...)
 		retVal.changedJobs = append(retVal.changedJobs, stationChangedJobs...)
 	}
-	for stationID, logicStation := range AllStations {
+	for _, logicStation := range SortedStations() {
+		stationID := logicStation.ID
 		changed, stationDeleteIds, stationNewJobs, stationChangedJobs := logicStation.ValidateJobs(FreightJobType)
 		changeFlagPerStation[stationID] = changed || changeFlagPerStation[stationID]
 
@@ -244,7 +333,8 @@ func updateAllJobs(srcJob *Job) updateAllPayload {
 		retVal.spawnJobs = append(retVal.spawnJobs, stationNewJobs...)
 		retVal.changedJobs = append(retVal.changedJobs, stationChangedJobs...)
 	}
-	for stationID, logicStation := range AllStations {
+	for _, logicStation := range SortedStations() {
+		stationID := logicStation.ID
 		changed, stationDeleteIds, stationNewJobs, stationChangedJobs := logicStation.ValidateJobs(ShuntingLoadJobType)
 		changeFlagPerStation[stationID] = changed || changeFlagPerStation[stationID]
 
@@ -252,7 +342,8 @@ func updateAllJobs(srcJob *Job) updateAllPayload {
 		retVal.spawnJobs = append(retVal.spawnJobs, stationNewJobs...)
 		retVal.changedJobs = append(retVal.changedJobs, stationChangedJobs...)
 	}
-	for stationID, logicStation := range AllStations {
+	for _, logicStation := range SortedStations() {
+		stationID := logicStation.ID
 		changed, stationDeleteIds, stationNewJobs, stationChangedJobs := logicStation.ValidateJobs(LogisticHaulJobType)
 		changeFlagPerStation[stationID] = changed || changeFlagPerStation[stationID]
 
@@ -261,7 +352,36 @@ func updateAllJobs(srcJob *Job) updateAllPayload {
 		retVal.changedJobs = append(retVal.changedJobs, stationChangedJobs...)
 	}
 
-	for stationID, isChanged := range changeFlagPerStation {
+	// wages follow supply and demand until a player reserves the job
+	for _, logicStation := range SortedStations() {
+		for _, j := range logicStation.JobQueue {
+			if j.jobReserved {
+				continue
+			}
+
+			oldWage := j.Wage
+			j.recalcWage()
+			if j.Wage == oldWage {
+				continue
+			}
+
+			changeFlagPerStation[logicStation.ID] = true
+			if !slices.Contains(retVal.changedJobs, j) {
+				retVal.changedJobs = append(retVal.changedJobs, j)
+			}
+		}
+	}
+
+	for _, stationID := range SortedStationIDs() {
+		isChanged := changeFlagPerStation[stationID]
+		if srcJob == nil {
+			// nothing finished, e.g. the world clock completed production
+			if isChanged {
+				retVal.notifyStationIDs = append(retVal.notifyStationIDs, stationID)
+			}
+			continue
+		}
+
 		if stationID == srcJob.StartingStationName || stationID == srcJob.TargetStationName {
 			retVal.notifyStationIDs = append(retVal.notifyStationIDs, stationID)
 			continue
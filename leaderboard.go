@@ -0,0 +1,196 @@
+package sharedjob
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+type (
+	LeaderboardWindow string
+	LeaderboardEntry  struct {
+		Username     string                `json:"username"`
+		Wages        int                   `json:"wages"`
+		JobsFinished int                   `json:"jobs_finished"`
+		CarsMoved    int                   `json:"cars_moved"`
+		Categories   map[CargoCategory]int `json:"categories"`
+	}
+)
+
+const (
+	LeaderboardSession LeaderboardWindow = "session"
+	LeaderboardDay     LeaderboardWindow = "day"
+	LeaderboardAllTime LeaderboardWindow = "all"
+)
+
+var sessionStart = time.Now()
+
+// Running leaderboard totals, updated with every finished job so that the archive never has
+// to be scanned. The day window adds up hourly buckets and is therefore exact to the hour.
+// Guarded by jobLock.
+var (
+	leaderboardAllTime = map[string]*LeaderboardEntry{}
+	leaderboardSession = map[string]*LeaderboardEntry{}
+	leaderboardHourly  = map[time.Time]map[string]*LeaderboardEntry{}
+)
+
+func (w LeaderboardWindow) String() string {
+	return string(w)
+}
+
+func resetLeaderboard() {
+	leaderboardAllTime = map[string]*LeaderboardEntry{}
+	leaderboardSession = map[string]*LeaderboardEntry{}
+	leaderboardHourly = map[time.Time]map[string]*LeaderboardEntry{}
+}
+
+// recordLeaderboard adds a finished job to the running totals
+func recordLeaderboard(j *Job) {
+	finished, ok := j.finishedEvent()
+	if !ok {
+		return
+	}
+
+	addToLeaderboard(leaderboardAllTime, finished.User, j)
+	if !finished.Time.Before(sessionStart) {
+		addToLeaderboard(leaderboardSession, finished.User, j)
+	}
+
+	dayStart := time.Now().Add(-24 * time.Hour).Truncate(time.Hour)
+	for hour := range leaderboardHourly {
+		if hour.Before(dayStart) {
+			delete(leaderboardHourly, hour)
+		}
+	}
+	if hour := finished.Time.Truncate(time.Hour); !hour.Before(dayStart) {
+		if _, ok := leaderboardHourly[hour]; !ok {
+			leaderboardHourly[hour] = make(map[string]*LeaderboardEntry)
+		}
+		addToLeaderboard(leaderboardHourly[hour], finished.User, j)
+	}
+}
+
+func addToLeaderboard(totals map[string]*LeaderboardEntry, userName string, j *Job) {
+	entry, ok := totals[userName]
+	if !ok {
+		entry = &LeaderboardEntry{
+			Username:   userName,
+			Categories: make(map[CargoCategory]int),
+		}
+		totals[userName] = entry
+	}
+
+	entry.Wages += j.Payout
+	entry.JobsFinished++
+	entry.CarsMoved += j.CarCount
+	entry.Categories[j.CargoType.Category()] += j.CarCount
+}
+
+func mergeLeaderboard(into map[string]*LeaderboardEntry, totals map[string]*LeaderboardEntry) {
+	for userName, entry := range totals {
+		merged, ok := into[userName]
+		if !ok {
+			merged = &LeaderboardEntry{
+				Username:   userName,
+				Categories: make(map[CargoCategory]int),
+			}
+			into[userName] = merged
+		}
+
+		merged.Wages += entry.Wages
+		merged.JobsFinished += entry.JobsFinished
+		merged.CarsMoved += entry.CarsMoved
+		for category, cars := range entry.Categories {
+			merged.Categories[category] += cars
+		}
+	}
+}
+
+// allTimeLeaderboard returns a copy of the all time totals sorted by username
+func allTimeLeaderboard() []LeaderboardEntry {
+	perUser := make(map[string]*LeaderboardEntry)
+	mergeLeaderboard(perUser, leaderboardAllTime)
+
+	entries := make([]LeaderboardEntry, 0, len(perUser))
+	for _, entry := range perUser {
+		entries = append(entries, *entry)
+	}
+	slices.SortFunc(entries, func(a, b LeaderboardEntry) int {
+		return strings.Compare(a.Username, b.Username)
+	})
+
+	return entries
+}
+
+func restoreLeaderboard(entries []LeaderboardEntry) {
+	leaderboardAllTime = make(map[string]*LeaderboardEntry, len(entries))
+	for _, entry := range entries {
+		restored := entry
+		if restored.Categories == nil {
+			restored.Categories = make(map[CargoCategory]int)
+		}
+		leaderboardAllTime[entry.Username] = &restored
+	}
+}
+
+// GetLeaderboard ranks players by finished jobs within the window. sortBy is one of
+// wages, jobs or cars and defaults to wages.
+func GetLeaderboard(window LeaderboardWindow, sortBy string) ([]LeaderboardEntry, error) {
+	perUser := make(map[string]*LeaderboardEntry)
+
+	jobLock.Lock()
+	switch window {
+	case LeaderboardAllTime:
+		mergeLeaderboard(perUser, leaderboardAllTime)
+	case LeaderboardSession:
+		mergeLeaderboard(perUser, leaderboardSession)
+	case LeaderboardDay:
+		dayStart := time.Now().Add(-24 * time.Hour).Truncate(time.Hour)
+		for hour, totals := range leaderboardHourly {
+			if !hour.Before(dayStart) {
+				mergeLeaderboard(perUser, totals)
+			}
+		}
+	default:
+		jobLock.Unlock()
+		return nil, fmt.Errorf("unknown leaderboard window %s", window)
+	}
+	jobLock.Unlock()
+
+	var value func(e LeaderboardEntry) int
+	switch sortBy {
+	case "", "wages":
+		value = func(e LeaderboardEntry) int { return e.Wages }
+	case "jobs":
+		value = func(e LeaderboardEntry) int { return e.JobsFinished }
+	case "cars":
+		value = func(e LeaderboardEntry) int { return e.CarsMoved }
+	default:
+		return nil, fmt.Errorf("unknown leaderboard sort %s", sortBy)
+	}
+
+	entries := make([]LeaderboardEntry, 0, len(perUser))
+	for _, entry := range perUser {
+		entries = append(entries, *entry)
+	}
+	slices.SortFunc(entries, func(a, b LeaderboardEntry) int {
+		if value(a) != value(b) {
+			return value(b) - value(a)
+		}
+
+		return strings.Compare(a.Username, b.Username)
+	})
+
+	return entries, nil
+}
+
+func (j *Job) finishedEvent() (JobEvent, bool) {
+	for i := len(j.history) - 1; i >= 0; i-- {
+		if j.history[i].Type == JobEventFinished {
+			return j.history[i], true
+		}
+	}
+
+	return JobEvent{}, false
+}
@@ -0,0 +1,62 @@
+package sharedjob
+
+import "testing"
+
+func TestArchiveLimitKeepsLeaderboardTotals(t *testing.T) {
+	ResetWorld()
+	SeedWorld(5)
+	Setup()
+	GrantLicenses("test", AllLicenses()...)
+
+	archiveMaxJobs = 2
+	defer func() { archiveMaxJobs = DefaultConfig().Persistence.ArchiveMaxJobs }()
+
+	progressCh := make(chan ProgressMessage, 100)
+	jobID := GetAllStationJobs(StationCM)[0].ID
+	chainID := jobID
+	for i := 0; i < 3; i++ {
+		ReserveJob("test", jobID)
+		TakeJob("test", jobID, progressCh)
+		ok, _, _, _, newJobs, _ := FinishJob("test", jobID, FinishReport{}, progressCh)
+		if !ok {
+			t.Fatalf("could not finish %s", jobID)
+		}
+		if len(newJobs) > 0 && newJobs[0].ChainID == chainID {
+			jobID = newJobs[0].ID
+		}
+	}
+
+	if len(archivedJobs) != 2 {
+		t.Fatalf("expected archive trimmed to 2 jobs, got %d", len(archivedJobs))
+	}
+	if _, err := FindJob(chainID); err == nil {
+		t.Error("expected the oldest job to be trimmed from the archive")
+	}
+	if _, err := FindJob(jobID); err != nil {
+		t.Errorf("expected the latest job in the archive: %s", err)
+	}
+
+	chain, err := GetChain(chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	finishedLegs := 0
+	for _, leg := range chain.Legs {
+		if leg.State == ChainLegFinished {
+			finishedLegs++
+		}
+	}
+	if finishedLegs != 2 {
+		t.Errorf("expected 2 archived legs in chain, got %d", finishedLegs)
+	}
+
+	for _, window := range []LeaderboardWindow{LeaderboardAllTime, LeaderboardSession, LeaderboardDay} {
+		entries, err := GetLeaderboard(window, "jobs")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || entries[0].JobsFinished != 3 {
+			t.Errorf("expected 3 finished jobs in %s leaderboard, got %+v", window, entries)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package sharedjob
+
+import (
+	"fmt"
+	"slices"
+)
+
+type License string
+
+const (
+	LicenseFreightHaul    License = "freight_haul"
+	LicenseShunting       License = "shunting"
+	LicenseLogisticalHaul License = "logistical_haul"
+	LicenseHazmat1        License = "hazmat1"
+	LicenseHazmat2        License = "hazmat2"
+	LicenseHazmat3        License = "hazmat3"
+	LicenseMilitary       License = "military"
+	LicenseTrainLength1   License = "train_length1"
+	LicenseTrainLength2   License = "train_length2"
+)
+
+// licenseOrder lists all licenses, prerequisites always come first
+var licenseOrder = []License{
+	LicenseFreightHaul,
+	LicenseShunting,
+	LicenseLogisticalHaul,
+	LicenseHazmat1,
+	LicenseHazmat2,
+	LicenseHazmat3,
+	LicenseMilitary,
+	LicenseTrainLength1,
+	LicenseTrainLength2,
+}
+
+var licensePrerequisite = map[License]License{
+	LicenseHazmat2:      LicenseHazmat1,
+	LicenseHazmat3:      LicenseHazmat2,
+	LicenseMilitary:     LicenseHazmat1,
+	LicenseTrainLength2: LicenseTrainLength1,
+}
+
+var jobTypeLicense = map[JobType]License{
+	FreightJobType:        LicenseFreightHaul,
+	LogisticHaulJobType:   LicenseLogisticalHaul,
+	ShuntingLoadJobType:   LicenseShunting,
+	ShuntingUnloadJobType: LicenseShunting,
+}
+
+// trains up to this many cars need no train length license
+const (
+	baseTrainLength    = 4
+	trainLength1Length = 8
+)
+
+func AllLicenses() []License {
+	return slices.Clone(licenseOrder)
+}
+
+func (l License) String() string {
+	return string(l)
+}
+
+func (l License) Prerequisite() (License, bool) {
+	prerequisite, ok := licensePrerequisite[l]
+	return prerequisite, ok
+}
+
+func (j *Job) updateLicenseRequired() {
+	required := []License{jobTypeLicense[j.JobType]}
+	if license := cargoCatalogue[j.CargoType].HazmatClass; license != "" {
+		required = append(required, license)
+	}
+	switch {
+	case j.CarCount > trainLength1Length:
+		required = append(required, LicenseTrainLength2)
+	case j.CarCount > baseTrainLength:
+		required = append(required, LicenseTrainLength1)
+	}
+
+	j.LicenseRequired = required
+}
+
+// missingLicenses returns the licenses the user lacks to work on the job
+func (j *Job) missingLicenses(userName string) []License {
+	if !economy.LicensesEnabled {
+		return nil
+	}
+
+	owned := GetWallet(userName).Licenses
+	missing := make([]License, 0)
+	for _, license := range j.LicenseRequired {
+		if !slices.Contains(owned, license) {
+			missing = append(missing, license)
+		}
+	}
+
+	return missing
+}
+
+// BuyLicense pays the license from the users wallet
+func BuyLicense(userName string, license License) error {
+	price, ok := economy.LicensePrices[license]
+	if !ok {
+		return fmt.Errorf("unknown license %s", license)
+	}
+
+	walletLock.Lock()
+	w := walletFor(userName)
+	if slices.Contains(w.Licenses, license) {
+		walletLock.Unlock()
+		return fmt.Errorf("license %s already owned", license)
+	}
+	if prerequisite, ok := license.Prerequisite(); ok && !slices.Contains(w.Licenses, prerequisite) {
+		walletLock.Unlock()
+		return fmt.Errorf("license %s requires %s", license, prerequisite)
+	}
+	if w.Balance < price {
+		walletLock.Unlock()
+		return fmt.Errorf("license %s costs %d, balance is %d", license, price, w.Balance)
+	}
+	// pay within the same lock so concurrent purchases cannot spend the balance twice
+	w.Licenses = append(w.Licenses, license)
+	bookLocked(w, "", -price, fmt.Sprintf("license %s", license))
+	walletLock.Unlock()
+
+	return nil
+}
+
+// GrantLicenses hands out licenses without payment. Used for bots and simulations.
+func GrantLicenses(userName string, licenses ...License) {
+	walletLock.Lock()
+	defer walletLock.Unlock()
+
+	w := walletFor(userName)
+	for _, license := range licenses {
+		if !slices.Contains(w.Licenses, license) {
+			w.Licenses = append(w.Licenses, license)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package sharedjob
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLicenseGatesReserveAndCanBeBought(t *testing.T) {
+	ResetWorld()
+	SeedWorld(3)
+	Setup()
+
+	var ammoniaJob *Job
+	for _, j := range AllStations[StationHB].JobQueue {
+		if j.CargoType == Ammonia {
+			ammoniaJob = j
+		}
+	}
+	if ammoniaJob == nil || !slices.Contains(ammoniaJob.LicenseRequired, LicenseHazmat2) {
+		t.Fatalf("expected an ammonia job requiring %s, got %+v", LicenseHazmat2, ammoniaJob)
+	}
+	if ReserveJob("newbie", ammoniaJob.ID) {
+		t.Fatal("expected reserve without hazmat license to fail")
+	}
+
+	if err := BuyLicense("newbie", LicenseHazmat1); err == nil {
+		t.Fatal("expected buying without balance to fail")
+	}
+	book("newbie", "", 100000, "test funds")
+	if err := BuyLicense("newbie", LicenseHazmat2); err == nil {
+		t.Fatal("expected buying hazmat2 without hazmat1 to fail")
+	}
+	if err := BuyLicense("newbie", LicenseHazmat1); err != nil {
+		t.Fatal(err)
+	}
+	if err := BuyLicense("newbie", LicenseHazmat2); err != nil {
+		t.Fatal(err)
+	}
+
+	GrantLicenses("newbie", LicenseTrainLength1, LicenseTrainLength2)
+	if !ReserveJob("newbie", ammoniaJob.ID) {
+		t.Error("expected reserve with all licenses to succeed")
+	}
+	if balance := GetWallet("newbie").Balance; balance != 100000-economy.LicensePrices[LicenseHazmat1]-economy.LicensePrices[LicenseHazmat2] {
+		t.Errorf("unexpected balance %d after buying licenses", balance)
+	}
+}
+
+func TestStartingLicensesCoverMostJobs(t *testing.T) {
+	ResetWorld()
+	SeedWorld(3)
+	Setup()
+
+	total, workable := 0, 0
+	for _, logicStation := range SortedStations() {
+		for _, j := range logicStation.JobQueue {
+			if cargoCatalogue[j.CargoType].HazmatClass != "" {
+				continue
+			}
+			total++
+			if len(j.missingLicenses("newbie")) == 0 {
+				workable++
+			}
+		}
+	}
+	if total == 0 || workable*2 < total {
+		t.Errorf("expected a new player to work most of %d non hazmat jobs, only %d are open", total, workable)
+	}
+}
+
+func TestConcurrentLicensePurchasesCannotOverspend(t *testing.T) {
+	ResetWorld()
+	book("buyer", "", economy.LicensePrices[LicenseHazmat1], "test funds")
+
+	done := make(chan error)
+	for _, license := range []License{LicenseHazmat1, LicenseLogisticalHaul} {
+		go func(license License) {
+			done <- BuyLicense("buyer", license)
+		}(license)
+	}
+	bought := 0
+	for i := 0; i < 2; i++ {
+		if err := <-done; err == nil {
+			bought++
+		}
+	}
+
+	if balance := GetWallet("buyer").Balance; balance < 0 || bought != 1 {
+		t.Errorf("expected exactly one purchase and no debt, got %d purchases and balance %d", bought, balance)
+	}
+}
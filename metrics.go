@@ -0,0 +1,150 @@
+package sharedjob
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Metrics in the Prometheus text exposition format.
+
+type histogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+var (
+	wsMessagesSent   atomic.Uint64
+	wsMessagesFailed atomic.Uint64
+
+	reserveToTakeSeconds = newHistogram(30, 60, 120, 300, 600, 900, 1800, 3600)
+	takeToFinishSeconds  = newHistogram(60, 300, 600, 900, 1800, 2700, 3600, 5400, 7200)
+)
+
+func newHistogram(buckets ...float64) *histogram {
+	return &histogram{
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	}
+}
+
+func (h *histogram) observe(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	v := d.Seconds()
+	for i, upper := range h.buckets {
+		if v <= upper {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+func (h *histogram) write(w io.Writer, name, help string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+	for i, upper := range h.buckets {
+		fmt.Fprintf(w, "%s_bucket{le=\"%g\"} %d\n", name, upper, h.counts[i])
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", name, h.count)
+	fmt.Fprintf(w, "%s_sum %g\n", name, h.sum)
+	fmt.Fprintf(w, "%s_count %d\n", name, h.count)
+}
+
+func jobState(j *Job) string {
+	switch {
+	case j.jobActive:
+		return "active"
+	case j.jobReserved:
+		return "reserved"
+	case j.jobSpawned:
+		return "spawned"
+	}
+
+	return "queued"
+}
+
+func WriteMetrics(w io.Writer) {
+	// job and track gauges read the queues, which must not change meanwhile
+	jobLock.Lock()
+	defer jobLock.Unlock()
+
+	fmt.Fprintln(w, "# HELP sharedjob_jobs Number of jobs by state, type and starting station.")
+	fmt.Fprintln(w, "# TYPE sharedjob_jobs gauge")
+	for _, logicStation := range SortedStations() {
+		counts := make(map[string]map[JobType]int)
+		for _, state := range []string{"queued", "spawned", "reserved", "active"} {
+			counts[state] = make(map[JobType]int)
+		}
+		for _, j := range logicStation.JobQueue {
+			counts[jobState(j)][j.JobType]++
+		}
+
+		for _, state := range []string{"queued", "spawned", "reserved", "active"} {
+			for _, jobType := range []JobType{ShuntingLoadJobType, FreightJobType, ShuntingUnloadJobType, LogisticHaulJobType} {
+				fmt.Fprintf(
+					w,
+					"sharedjob_jobs{station=\"%s\",type=\"%s\",state=\"%s\"} %d\n",
+					logicStation.ID, jobType, state, counts[state][jobType],
+				)
+			}
+		}
+	}
+
+	fmt.Fprintln(w, "# HELP sharedjob_spawned_jobs Number of spawned jobs per station.")
+	fmt.Fprintln(w, "# TYPE sharedjob_spawned_jobs gauge")
+	for _, logicStation := range SortedStations() {
+		fmt.Fprintf(w, "sharedjob_spawned_jobs{station=\"%s\"} %d\n", logicStation.ID, logicStation.CountSpawnedJobs())
+	}
+
+	fmt.Fprintln(w, "# HELP sharedjob_tracks Number of free and occupied tracks per track type.")
+	fmt.Fprintln(w, "# TYPE sharedjob_tracks gauge")
+	for _, trackType := range []TrackTypeID{InputTrackType, StorageTrackType, OutputTrackType, LoadingTrackType} {
+		total, occupied := 0, 0
+		for _, logicStation := range SortedStations() {
+			total += len(logicStation.GetAllFullTrackNames(trackType))
+			occupied += len(logicStation.GetOccupiedTrackNames(trackType))
+		}
+
+		fmt.Fprintf(w, "sharedjob_tracks{track_type=\"%s\",state=\"free\"} %d\n", trackType, total-occupied)
+		fmt.Fprintf(w, "sharedjob_tracks{track_type=\"%s\",state=\"occupied\"} %d\n", trackType, occupied)
+	}
+
+	connected, bots := 0, 0
+	for _, playerObj := range GetPlayers() {
+		if playerObj.IsBot() {
+			bots++
+		} else {
+			connected++
+		}
+	}
+
+	fmt.Fprintln(w, "# HELP sharedjob_connected_players Number of players connected over websocket.")
+	fmt.Fprintln(w, "# TYPE sharedjob_connected_players gauge")
+	fmt.Fprintf(w, "sharedjob_connected_players %d\n", connected)
+
+	fmt.Fprintln(w, "# HELP sharedjob_bot_players Number of in-process bot players.")
+	fmt.Fprintln(w, "# TYPE sharedjob_bot_players gauge")
+	fmt.Fprintf(w, "sharedjob_bot_players %d\n", bots)
+
+	fmt.Fprintln(w, "# HELP sharedjob_ws_messages_sent_total Websocket progress messages sent.")
+	fmt.Fprintln(w, "# TYPE sharedjob_ws_messages_sent_total counter")
+	fmt.Fprintf(w, "sharedjob_ws_messages_sent_total %d\n", wsMessagesSent.Load())
+
+	fmt.Fprintln(w, "# HELP sharedjob_ws_messages_failed_total Websocket progress messages that could not be sent.")
+	fmt.Fprintln(w, "# TYPE sharedjob_ws_messages_failed_total counter")
+	fmt.Fprintf(w, "sharedjob_ws_messages_failed_total %d\n", wsMessagesFailed.Load())
+
+	reserveToTakeSeconds.write(w, "sharedjob_job_reserve_to_take_seconds", "Time between reserving and taking a job.")
+	takeToFinishSeconds.write(w, "sharedjob_job_take_to_finish_seconds", "Time between taking and finishing a job.")
+}
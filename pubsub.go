@@ -4,15 +4,25 @@ import (
 	"fmt"
 	"net/http"
 	"slices"
+	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/sirupsen/logrus"
 )
 
 var upgrader = websocket.Upgrader{}
-var players = []*Player{}
+
+// players and their subscriptions are guarded by playersLock
+var (
+	players     = []*Player{}
+	playersLock = sync.Mutex{}
+)
 
 func getPlayer(wsConn *websocket.Conn) *Player {
+	playersLock.Lock()
+	defer playersLock.Unlock()
+
 	for _, playerObj := range players {
 		if playerObj.wsConn == wsConn {
 			return playerObj
@@ -22,9 +32,19 @@ func getPlayer(wsConn *websocket.Conn) *Player {
 }
 
 func GetPlayers() []*Player {
+	playersLock.Lock()
+	defer playersLock.Unlock()
+
 	return slices.Clone(players)
 }
 
+func addPlayer(playerObj *Player) {
+	playersLock.Lock()
+	defer playersLock.Unlock()
+
+	players = append(players, playerObj)
+}
+
 type (
 	clientWelcomeMessage struct {
 		Conn     *websocket.Conn `json:"-"`
@@ -39,13 +59,21 @@ type (
 		wsConn         *websocket.Conn
 		subbedStations []StationID
 		Username       string
+		bot            bool
 	}
+	// ProgressMessage tells subscribers to reload the station. JobID and Event name the job
+	// change that caused it, if any.
 	ProgressMessage struct {
-		StationID StationID `json:"station_id"`
+		StationID StationID    `json:"station_id"`
+		JobID     string       `json:"job_id,omitempty"`
+		Event     JobEventType `json:"event,omitempty"`
 	}
 )
 
 func (p *Player) IsSubbedToStation(s StationID) bool {
+	playersLock.Lock()
+	defer playersLock.Unlock()
+
 	for _, subbedStation := range p.subbedStations {
 		if subbedStation == s {
 			return true
@@ -55,7 +83,14 @@ func (p *Player) IsSubbedToStation(s StationID) bool {
 	return false
 }
 
+func (p *Player) IsBot() bool {
+	return p.bot
+}
+
 func (p *Player) GetSubbedStations() []StationID {
+	playersLock.Lock()
+	defer playersLock.Unlock()
+
 	return slices.Clone(p.subbedStations)
 }
 
@@ -81,7 +116,7 @@ func HandleWebsocket(
 		return
 	}
 
-	players = append(players, &Player{
+	addPlayer(&Player{
 		wsConn:         conn,
 		Username:       welcome.Username,
 		subbedStations: make([]StationID, 0),
@@ -105,6 +140,9 @@ func HandleWebsocket(
 }
 
 func cleanUpClosedConnection(conn *websocket.Conn) {
+	playersLock.Lock()
+	defer playersLock.Unlock()
+
 	for index, playerObj := range players {
 		if playerObj.wsConn == conn {
 			players = slices.Delete(players, index, index+1)
@@ -113,6 +151,21 @@ func cleanUpClosedConnection(conn *websocket.Conn) {
 	}
 }
 
+// CloseAllConnections sends a close frame to every connected player. The read loop in
+// HandleWebsocket then cleans up once the client answers.
+func CloseAllConnections(deadline time.Time) {
+	closeMsg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	for _, playerObj := range GetPlayers() {
+		if playerObj.wsConn == nil {
+			continue
+		}
+
+		if err := playerObj.wsConn.WriteControl(websocket.CloseMessage, closeMsg, deadline); err != nil {
+			logrus.WithError(err).WithField("username", playerObj.Username).Warn("could not send close frame")
+		}
+	}
+}
+
 func StartWSProcessor() (chan<- clientMessage, chan<- ProgressMessage) {
 	clientCh := make(chan clientMessage)
 	progressCh := make(chan ProgressMessage)
@@ -136,6 +189,7 @@ func StartWSProcessor() (chan<- clientMessage, chan<- ProgressMessage) {
 					"unsubscribe": msg.Unsub,
 				}).Info("received client message")
 
+				playersLock.Lock()
 				if msg.Unsub {
 					for i, stationID := range playerObj.subbedStations {
 						if stationID == msg.StationID {
@@ -146,13 +200,16 @@ func StartWSProcessor() (chan<- clientMessage, chan<- ProgressMessage) {
 				} else {
 					playerObj.subbedStations = append(playerObj.subbedStations, msg.StationID)
 				}
+				playersLock.Unlock()
 			case msg := <-progressCh:
-				for _, playerObj := range players {
+				for _, playerObj := range GetPlayers() {
 					if playerObj.IsSubbedToStation(msg.StationID) {
 						if err := playerObj.wsConn.WriteJSON(msg); err != nil {
+							wsMessagesFailed.Add(1)
 							logrus.WithError(err).Error("could not send progress message")
 							continue
 						}
+						wsMessagesSent.Add(1)
 
 						logrus.WithFields(logrus.Fields{
 							"station_id": msg.StationID,
@@ -0,0 +1,169 @@
+package sharedjob
+
+import (
+	"fmt"
+	"slices"
+)
+
+const rebalanceReason = "rebalance storage"
+
+// storageLoad returns used and total storage tracks. Waiting cargo and empty cars count
+// as used tracks too, they have to stand somewhere.
+func (s *LogicStation) storageLoad() (used, total int) {
+	return s.storageLoadWith(0)
+}
+
+// storageLoadWith is storageLoad with extra cars waiting at the station
+func (s *LogicStation) storageLoadWith(extraCars int) (used, total int) {
+	total = len(s.GetAllFullTrackNames(StorageTrackType))
+	used = len(s.GetOccupiedTrackNames(StorageTrackType))
+
+	waitingCars := len(s.emptyCars) + extraCars
+	for _, count := range s.cargoBuffer {
+		waitingCars += count
+	}
+	used += (waitingCars + economy.MaxCarsPerJob - 1) / economy.MaxCarsPerJob
+
+	return min(used, total), total
+}
+
+// freeStorageAfter returns the free storage tracks once all hauls on their way to the station
+// and another haul of the given number of cars have arrived.
+func (s *LogicStation) freeStorageAfter(cars int) int {
+	used, total := s.storageLoadWith(s.inboundHaulCars() + cars)
+	return total - used
+}
+
+// inboundHaulCars counts the cars of logistic hauls heading to the station
+func (s *LogicStation) inboundHaulCars() int {
+	cars := 0
+	for _, logicStation := range SortedStations() {
+		for _, j := range logicStation.JobQueue {
+			if j.JobType == LogisticHaulJobType && j.TargetStationName == s.ID && j.StartingStationName != s.ID {
+				cars += j.CarCount
+			}
+		}
+	}
+
+	return cars
+}
+
+func (s *LogicStation) hasPendingRebalance() bool {
+	return slices.ContainsFunc(s.JobQueue, func(j *Job) bool {
+		return j.JobType == LogisticHaulJobType && !j.jobActive
+	})
+}
+
+// rebalanceStorage creates logistic haul jobs moving waiting cargo or empties away from
+// stations with full storage. Each station has at most one haul waiting to be taken.
+// Expects jobLock to be held.
+func rebalanceStorage() []*Job {
+	newJobs := make([]*Job, 0)
+	for _, logicStation := range SortedStations() {
+		used, total := logicStation.storageLoad()
+		if total == 0 || used < total || logicStation.hasPendingRebalance() {
+			continue
+		}
+
+		if j := logicStation.rebalanceCargo(); j != nil {
+			newJobs = append(newJobs, j)
+			continue
+		}
+		if j := logicStation.rebalanceEmptyCars(); j != nil {
+			newJobs = append(newJobs, j)
+		}
+	}
+
+	return newJobs
+}
+
+// rebalanceCargo moves buffered cargo to another station producing the same cargo where
+// it is loaded into regular load jobs.
+func (s *LogicStation) rebalanceCargo() *Job {
+	cargoTypes := make([]CargoType, 0, len(s.cargoBuffer))
+	for cargo, count := range s.cargoBuffer {
+		if count > 0 {
+			cargoTypes = append(cargoTypes, cargo)
+		}
+	}
+	slices.Sort(cargoTypes)
+
+	for _, cargo := range cargoTypes {
+		count := min(s.cargoBuffer[cargo], economy.MaxCarsPerJob)
+		target := s.rebalanceTarget(count, func(candidate *LogicStation) bool {
+			return slices.ContainsFunc(candidate.Processor, func(proc *StationProcessor) bool {
+				return proc.output == cargo
+			})
+		})
+		if target == nil {
+			continue
+		}
+
+		s.cargoBuffer[cargo] -= count
+		origin := &jobOrigin{cars: s.takeBufferedCars(cargo, count)}
+		return s.addJob(target.ID, LogisticHaulJobType, count, cargo, origin, rebalanceReason)
+	}
+
+	return nil
+}
+
+func (s *LogicStation) rebalanceEmptyCars() *Job {
+	carTypes := make([]CarType, 0)
+	for _, car := range s.emptyCars {
+		if !slices.Contains(carTypes, car.Type) {
+			carTypes = append(carTypes, car.Type)
+		}
+	}
+	slices.Sort(carTypes)
+
+	for _, carType := range carTypes {
+		count := min(s.countEmptyCars(carType), economy.MaxCarsPerJob)
+		target := s.rebalanceTarget(count, func(candidate *LogicStation) bool {
+			return candidate.needsCarType(carType)
+		})
+		if target == nil {
+			continue
+		}
+
+		cargo := emptyCargoByCarType[carType]
+		cars := s.takeEmptyCarType(carType, count)
+		return s.addJob(target.ID, LogisticHaulJobType, len(cars), cargo, &jobOrigin{cars: cars}, rebalanceReason)
+	}
+
+	return nil
+}
+
+// rebalanceTarget picks the accepted station with the most free storage once the haul of
+// cars and all hauls already heading there have arrived, the closest on a tie. Stations that
+// would be left without a free storage track are never picked, so they never have to
+// rebalance the cars right back.
+func (s *LogicStation) rebalanceTarget(cars int, accept func(candidate *LogicStation) bool) *LogicStation {
+	var (
+		best     *LogicStation
+		bestFree = 0
+	)
+	for _, candidate := range SortedStations() {
+		if candidate == s || !accept(candidate) {
+			continue
+		}
+
+		free := candidate.freeStorageAfter(cars)
+		if free > bestFree || (free == bestFree && best != nil && StationDistance(s.ID, candidate.ID) < StationDistance(s.ID, best.ID)) {
+			best, bestFree = candidate, free
+		}
+	}
+
+	return best
+}
+
+// procLogisticHaul stores delivered cars at the station. Empties join the pool, loaded
+// cars wait for a load job on the track they were delivered to.
+func (s *LogicStation) procLogisticHaul(j *Job) []*Job {
+	if j.CargoType.IsEmpty() {
+		s.emptyCars = append(s.emptyCars, j.Cars...)
+		return nil
+	}
+
+	origin := &jobOrigin{track: j.TargetTrack, cars: j.Cars}
+	return []*Job{s.addJob(s.ID, ShuntingLoadJobType, j.CarCount, j.CargoType, origin, fmt.Sprintf("follow-up of %s", j.ID))}
+}
@@ -0,0 +1,92 @@
+package sharedjob
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRebalanceMovesBufferedCargoToSisterStation(t *testing.T) {
+	ResetWorld()
+	SeedWorld(11)
+	Setup()
+
+	// IMW needs room for the whole haul
+	AllStations[StationIMW].JobQueue = nil
+
+	ime := AllStations[StationIME]
+	_, total := ime.storageLoad()
+	ime.cargoBuffer[IronOre] = total * economy.MaxCarsPerJob
+
+	haulJobs := rebalanceStorage()
+	if len(haulJobs) != 1 {
+		t.Fatalf("expected one rebalance job, got %d", len(haulJobs))
+	}
+	haul := haulJobs[0]
+	if haul.JobType != LogisticHaulJobType || haul.CargoType != IronOre || haul.TargetStationName != StationIMW {
+		t.Fatalf("expected iron ore haul to IMW, got %s %s to %s", haul.JobType, haul.CargoType, haul.TargetStationName)
+	}
+	if got := len(rebalanceStorage()); got != 0 {
+		t.Errorf("expected no second haul while one is pending, got %d", got)
+	}
+
+	loadJobs := AllStations[StationIMW].ProcessJob(haul)
+	if len(loadJobs) != 1 || loadJobs[0].JobType != ShuntingLoadJobType || loadJobs[0].Cars[0] != haul.Cars[0] {
+		t.Errorf("expected a load job with the hauled cars, got %+v", loadJobs)
+	}
+}
+
+func TestRebalancedEmptiesDoNotComeBack(t *testing.T) {
+	ResetWorld()
+	SeedWorld(11)
+	Setup()
+
+	for _, sid := range []StationID{StationHB, StationOWC, StationOWN} {
+		AllStations[sid].JobQueue = nil
+	}
+	hb := AllStations[StationHB]
+	_, total := hb.storageLoad()
+	visited := make(map[string][]StationID)
+	// two tracks worth of cars more than fit keep HB full for a few rounds
+	for i := 0; i < (total+2)*economy.MaxCarsPerJob; i++ {
+		car := newCar(CrudeOil)
+		hb.emptyCars = append(hb.emptyCars, car)
+		visited[car.ID] = []StationID{StationHB}
+	}
+
+	// hauls of one round are taken but only arrive in the next, so later rounds have to
+	// account for cars already on their way
+	inFlight := make([]*Job, 0)
+	moved := 0
+	for round := 0; round < 10; round++ {
+		newHauls := rebalanceStorage()
+		for _, haul := range newHauls {
+			haul.jobActive = true
+		}
+
+		for _, haul := range inFlight {
+			start := AllStations[haul.StartingStationName]
+			start.JobQueue = slices.DeleteFunc(start.JobQueue, func(j *Job) bool { return j.ID == haul.ID })
+
+			target := AllStations[haul.TargetStationName]
+			target.ProcessJob(haul)
+			if used, total := target.storageLoad(); used >= total {
+				t.Errorf("round %d: haul %s left %s without free storage", round, haul.ID, target.ID)
+			}
+			for _, car := range haul.Cars {
+				if slices.Contains(visited[car.ID], target.ID) {
+					t.Errorf("round %d: car %s came back to %s", round, car.ID, target.ID)
+				}
+				visited[car.ID] = append(visited[car.ID], target.ID)
+			}
+			moved += haul.CarCount
+		}
+		inFlight = newHauls
+	}
+
+	if moved == 0 {
+		t.Fatal("expected empties to leave the full HB storage")
+	}
+	if used, total := hb.storageLoad(); used >= total {
+		t.Errorf("expected HB storage to have room after rebalancing, %d of %d used", used, total)
+	}
+}
@@ -0,0 +1,335 @@
+package sharedjob
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Persisted world state. Processors are not stored; they come from Setup and only their
+// buffers are restored, so Setup has to run before LoadState.
+
+type (
+	savedJob struct {
+		ID                  string      `json:"id"`
+		JobType             JobType     `json:"type"`
+		StartingStationName StationID   `json:"starting_station"`
+		StartingTrack       string      `json:"starting_track"`
+		StartTrackType      TrackTypeID `json:"start_track_type"`
+		FixedStartTrack     bool        `json:"fixed_start_track"`
+		LoadingTrack        string      `json:"loading_track"`
+		TargetStationName   StationID   `json:"target_station"`
+		TargetTrack         string      `json:"target_track"`
+		TargetTrackType     TrackTypeID `json:"target_track_type"`
+		CarCount            int         `json:"car_count"`
+		Cars                []Car       `json:"cars"`
+		CargoType           CargoType   `json:"cargo_type"`
+		ChainID             string      `json:"chain_id"`
+		Wage                int         `json:"wage"`
+		WageMultiplier      float64     `json:"wage_multiplier"`
+		Deadline            *time.Time  `json:"deadline,omitempty"`
+		Payout              int         `json:"payout"`
+		Reserved            bool        `json:"reserved"`
+		Active              bool        `json:"active"`
+		AssignedUser        string      `json:"assigned_user"`
+		Spawned             bool        `json:"spawned"`
+		ReservedAt          time.Time   `json:"reserved_at"`
+		TakenAt             time.Time   `json:"taken_at"`
+		History             []JobEvent  `json:"history"`
+	}
+	savedStation struct {
+		ID               StationID           `json:"id"`
+		Jobs             []savedJob          `json:"jobs"`
+		LastJobNum       int                 `json:"last_job_num"`
+		LastProcIndex    int                 `json:"last_proc_index"`
+		CargoBuffer      map[CargoType]int   `json:"cargo_buffer"`
+		ProcessorBuffers []map[CargoType]int `json:"processor_buffers"`
+		// ProcessorProduction is missing in saves from before time based production
+		ProcessorProduction [][]PendingProduction `json:"processor_production"`
+		EmptyCars           []Car                 `json:"empty_cars"`
+		// CargoBufferCars may hold fewer cars than CargoBuffer in older saves
+		CargoBufferCars map[CargoType][]Car `json:"cargo_buffer_cars"`
+	}
+	worldState struct {
+		Seed       int64          `json:"seed"`
+		RandDraws  uint64         `json:"rand_draws"`
+		LastCarNum int            `json:"last_car_num"`
+		WorldTime  time.Time      `json:"world_time"`
+		SavedAt    time.Time      `json:"saved_at"`
+		Stations   []savedStation `json:"stations"`
+		Archive    []savedJob     `json:"archive"`
+		Wallets    []Wallet       `json:"wallets"`
+		// Leaderboard holds the all time totals, the archive may be trimmed already
+		Leaderboard []LeaderboardEntry `json:"leaderboard"`
+	}
+)
+
+func SaveState(path string) error {
+	jobLock.Lock()
+	state := worldState{
+		Seed:       worldSeed,
+		RandDraws:  worldSource.getDraws(),
+		LastCarNum: lastCarNum,
+		WorldTime:  worldTime,
+		SavedAt:    time.Now(),
+		Stations:   make([]savedStation, 0, len(AllStations)),
+	}
+	for _, logicStation := range SortedStations() {
+		state.Stations = append(state.Stations, saveStation(logicStation))
+	}
+	state.Archive = make([]savedJob, 0, len(archivedJobs))
+	for _, j := range archivedJobs {
+		state.Archive = append(state.Archive, saveJob(j))
+	}
+	state.Leaderboard = allTimeLeaderboard()
+	jobLock.Unlock()
+
+	walletLock.Lock()
+	state.Wallets = make([]Wallet, 0, len(wallets))
+	for _, w := range wallets {
+		saved := *w
+		saved.Licenses = slices.Clone(w.Licenses)
+		saved.Ledger = slices.Clone(w.Ledger)
+		state.Wallets = append(state.Wallets, saved)
+	}
+	walletLock.Unlock()
+	slices.SortFunc(state.Wallets, func(a, b Wallet) int {
+		return strings.Compare(a.Username, b.Username)
+	})
+
+	raw, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode state: %w", err)
+	}
+
+	// write to a temp file first so a crash mid-write never leaves a broken save behind
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, raw, 0o644); err != nil {
+		return fmt.Errorf("could not write state: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("could not replace state: %w", err)
+	}
+
+	logrus.WithField("path", path).Info("saved state")
+	return nil
+}
+
+// LoadState restores jobs and buffers from path. A missing file is not an error.
+// Returns the seed stored in the save, or 0 if nothing was loaded. The world RNG continues
+// from where the save left off.
+func LoadState(path string) (int64, error) {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("could not read state: %w", err)
+	}
+
+	state := worldState{}
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return 0, fmt.Errorf("could not decode state: %w", err)
+	}
+
+	jobLock.Lock()
+	defer jobLock.Unlock()
+
+	lastCarNum = state.LastCarNum
+	if !state.WorldTime.IsZero() {
+		worldTime = state.WorldTime
+	}
+	for _, stationState := range state.Stations {
+		logicStation := GetStation(stationState.ID)
+		if logicStation == nil {
+			return 0, fmt.Errorf("unknown station %s in state", stationState.ID)
+		}
+
+		if err := loadStation(logicStation, stationState); err != nil {
+			return 0, err
+		}
+	}
+
+	resetArchive()
+	resetLeaderboard()
+	for _, saved := range state.Archive {
+		j := loadJob(saved)
+		archiveJob(j)
+		recordLeaderboard(j)
+	}
+	// saves from before running totals only know the archived jobs
+	if state.Leaderboard != nil {
+		restoreLeaderboard(state.Leaderboard)
+	}
+
+	// continue the random sequence where the save left off. Restored last so that cars
+	// generated for legacy saves above do not shift it.
+	if state.Seed != 0 {
+		restoreWorldRand(state.Seed, state.RandDraws)
+	}
+
+	walletLock.Lock()
+	wallets = make(map[string]*Wallet, len(state.Wallets))
+	for i := range state.Wallets {
+		// wallets saved before licenses existed get the starting set
+		if state.Wallets[i].Licenses == nil {
+			state.Wallets[i].Licenses = slices.Clone(economy.StartingLicenses)
+		}
+		wallets[state.Wallets[i].Username] = &state.Wallets[i]
+	}
+	walletLock.Unlock()
+
+	logrus.WithFields(logrus.Fields{
+		"path":     path,
+		"saved_at": state.SavedAt,
+	}).Info("loaded state")
+
+	return state.Seed, nil
+}
+
+// saveStation copies the station state, the copy is encoded after jobLock is released
+func saveStation(s *LogicStation) savedStation {
+	stationState := savedStation{
+		ID:                  s.ID,
+		Jobs:                make([]savedJob, 0, len(s.JobQueue)),
+		LastJobNum:          s.lastJobNum,
+		LastProcIndex:       s.lastProcIndex,
+		CargoBuffer:         maps.Clone(s.cargoBuffer),
+		ProcessorBuffers:    make([]map[CargoType]int, 0, len(s.Processor)),
+		ProcessorProduction: make([][]PendingProduction, 0, len(s.Processor)),
+		EmptyCars:           slices.Clone(s.emptyCars),
+		CargoBufferCars:     make(map[CargoType][]Car, len(s.cargoBufferCars)),
+	}
+	for cType, cars := range s.cargoBufferCars {
+		stationState.CargoBufferCars[cType] = slices.Clone(cars)
+	}
+
+	for _, j := range s.JobQueue {
+		stationState.Jobs = append(stationState.Jobs, saveJob(j))
+	}
+
+	for _, proc := range s.Processor {
+		production := slices.Clone(proc.production)
+		for i := range production {
+			production[i].Cars = slices.Clone(production[i].Cars)
+		}
+		stationState.ProcessorBuffers = append(stationState.ProcessorBuffers, maps.Clone(proc.buffer))
+		stationState.ProcessorProduction = append(stationState.ProcessorProduction, production)
+	}
+
+	return stationState
+}
+
+func loadStation(s *LogicStation, stationState savedStation) error {
+	if len(stationState.ProcessorBuffers) != len(s.Processor) {
+		return fmt.Errorf(
+			"station %s has %d processors but state has %d",
+			s.ID, len(s.Processor), len(stationState.ProcessorBuffers),
+		)
+	}
+
+	s.JobQueue = make([]*Job, 0, len(stationState.Jobs))
+	for _, saved := range stationState.Jobs {
+		s.JobQueue = append(s.JobQueue, loadJob(saved))
+	}
+
+	s.lastJobNum = stationState.LastJobNum
+	s.lastProcIndex = stationState.LastProcIndex
+	s.emptyCars = slices.Clone(stationState.EmptyCars)
+	s.cargoBuffer = make(map[CargoType]int)
+	for cType, count := range stationState.CargoBuffer {
+		s.cargoBuffer[cType] = count
+	}
+	s.cargoBufferCars = make(map[CargoType][]Car)
+	for cType, cars := range stationState.CargoBufferCars {
+		s.cargoBufferCars[cType] = slices.Clone(cars)
+	}
+	for i, buffer := range stationState.ProcessorBuffers {
+		s.Processor[i].buffer = make(map[CargoType]int)
+		for cType, count := range buffer {
+			s.Processor[i].buffer[cType] = count
+		}
+	}
+	for i, production := range stationState.ProcessorProduction {
+		if i < len(s.Processor) {
+			s.Processor[i].production = slices.Clone(production)
+		}
+	}
+
+	return nil
+}
+
+func saveJob(j *Job) savedJob {
+	return savedJob{
+		ID:                  j.ID,
+		JobType:             j.JobType,
+		StartingStationName: j.StartingStationName,
+		StartingTrack:       j.StartingTrack,
+		StartTrackType:      j.startTrackType,
+		FixedStartTrack:     j.fixedStartTrack,
+		LoadingTrack:        j.LoadingTrack,
+		TargetStationName:   j.TargetStationName,
+		TargetTrack:         j.TargetTrack,
+		TargetTrackType:     j.targetTrackType,
+		CarCount:            j.CarCount,
+		Cars:                slices.Clone(j.Cars),
+		CargoType:           j.CargoType,
+		ChainID:             j.ChainID,
+		Wage:                j.Wage,
+		WageMultiplier:      j.WageMultiplier,
+		Deadline:            j.Deadline,
+		Payout:              j.Payout,
+		Reserved:            j.jobReserved,
+		Active:              j.jobActive,
+		AssignedUser:        j.jobAssignedUser,
+		Spawned:             j.jobSpawned,
+		ReservedAt:          j.reservedAt,
+		TakenAt:             j.takenAt,
+		History:             slices.Clone(j.history),
+	}
+}
+
+func loadJob(saved savedJob) *Job {
+	j := &Job{
+		ID:                  saved.ID,
+		JobType:             saved.JobType,
+		StartingStationName: saved.StartingStationName,
+		StartingTrack:       saved.StartingTrack,
+		startTrackType:      saved.StartTrackType,
+		fixedStartTrack:     saved.FixedStartTrack,
+		LoadingTrack:        saved.LoadingTrack,
+		TargetStationName:   saved.TargetStationName,
+		TargetTrack:         saved.TargetTrack,
+		targetTrackType:     saved.TargetTrackType,
+		CarCount:            saved.CarCount,
+		Cars:                saved.Cars,
+		CargoType:           saved.CargoType,
+		ChainID:             saved.ChainID,
+		Wage:                saved.Wage,
+		WageMultiplier:      saved.WageMultiplier,
+		Deadline:            saved.Deadline,
+		Payout:              saved.Payout,
+		jobReserved:         saved.Reserved,
+		jobActive:           saved.Active,
+		jobAssignedUser:     saved.AssignedUser,
+		jobSpawned:          saved.Spawned,
+		reservedAt:          saved.ReservedAt,
+		takenAt:             saved.TakenAt,
+		history:             saved.History,
+	}
+	if j.ChainID == "" {
+		j.ChainID = j.ID
+	}
+	// saves from before cars existed get their cars now
+	j.resizeCars()
+	j.updateLicenseRequired()
+
+	return j
+}
@@ -0,0 +1,124 @@
+package sharedjob
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStateRoundTrip(t *testing.T) {
+	ResetWorld()
+	SeedWorld(7)
+	Setup()
+	GrantLicenses("test", AllLicenses()...)
+
+	progressCh := make(chan ProgressMessage, 100)
+	j := GetAllStationJobs(StationCM)[0]
+	ReserveJob("test", j.ID)
+	TakeJob("test", j.ID, progressCh)
+	FinishJob("test", j.ID, FinishReport{}, progressCh)
+
+	// leave a batch in production
+	sm := AllStations[StationSM]
+	sm.ProcessJob(sm.AddJob(StationSM, ShuntingUnloadJobType, 1, Coal, "test delivery"))
+	sm.ProcessJob(sm.AddJob(StationSM, ShuntingUnloadJobType, 2, IronOre, "test delivery"))
+
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	if err := SaveState(statePath); err != nil {
+		t.Fatal(err)
+	}
+	before := describeWorld()
+	nextRand := worldRand.Int63()
+	walletBefore := GetWallet("test")
+
+	ResetWorld()
+	SeedWorld(1)
+	Setup()
+	seed, err := LoadState(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if seed != 7 {
+		t.Errorf("expected seed 7, got %d", seed)
+	}
+	if got := worldRand.Int63(); got != nextRand {
+		t.Errorf("expected the random sequence to continue with %d, got %d", nextRand, got)
+	}
+	if wallet := GetWallet("test"); wallet.Balance != walletBefore.Balance || len(wallet.Ledger) != len(walletBefore.Ledger) {
+		t.Errorf("expected wallet %+v, got %+v", walletBefore, wallet)
+	}
+	if after := describeWorld(); fmt.Sprint(before) != fmt.Sprint(after) {
+		t.Errorf("state differs after load:\n%v\n%v", before, after)
+	}
+}
+
+func describeWorld() []string {
+	lines := make([]string, 0)
+	for _, station := range SortedStations() {
+		for _, j := range station.JobQueue {
+			lines = append(lines, fmt.Sprintf(
+				"%s %s %s %d %v %d %v %v %s",
+				j.ID, j.StartingTrack, j.TargetTrack, j.CarCount, j.Cars, j.Wage, j.IsReserved(), j.IsActive(), j.GetAssignedUser(),
+			))
+		}
+		lines = append(lines, fmt.Sprintf("%s %d %v %v", station.ID, station.lastJobNum, station.cargoBuffer, station.emptyCars))
+		for _, proc := range station.Processor {
+			lines = append(lines, fmt.Sprintf("%s %v %v", proc.output, proc.buffer, proc.production))
+		}
+	}
+	lines = append(lines, WorldTime().UTC().String())
+
+	return lines
+}
+
+func TestLegacyStateKeepsRandomSequence(t *testing.T) {
+	ResetWorld()
+	SeedWorld(7)
+	Setup()
+
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	if err := SaveState(statePath); err != nil {
+		t.Fatal(err)
+	}
+	nextRand := worldRand.Int63()
+
+	// saves from before cars existed have no cars on their jobs
+	raw, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := worldState{}
+	if err := json.Unmarshal(raw, &state); err != nil {
+		t.Fatal(err)
+	}
+	for i := range state.Stations {
+		for k := range state.Stations[i].Jobs {
+			state.Stations[i].Jobs[k].Cars = nil
+		}
+	}
+	if raw, err = json.Marshal(state); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(statePath, raw, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ResetWorld()
+	SeedWorld(1)
+	Setup()
+	if _, err := LoadState(statePath); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := worldRand.Int63(); got != nextRand {
+		t.Errorf("expected generating legacy cars to leave the random sequence at %d, got %d", nextRand, got)
+	}
+	for _, j := range AllStations[StationCM].JobQueue {
+		if len(j.Cars) != j.CarCount {
+			t.Errorf("expected legacy job %s to get %d cars, got %d", j.ID, j.CarCount, len(j.Cars))
+		}
+	}
+}
@@ -2,13 +2,15 @@ package sharedjob
 
 import (
 	"fmt"
-	"math/rand"
+	"maps"
 	"slices"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
 const (
+	// MAX_CARS_PER_JOB is the default for economy.max_cars_per_job
 	MAX_CARS_PER_JOB int = 12
 )
 
@@ -16,10 +18,33 @@ type StationProcessor struct {
 	allowedInput   []CargoType
 	blueprint      map[CargoType]int
 	buffer         map[CargoType]int
+	production     []PendingProduction
 	output         CargoType
 	targetStations []StationID
 }
 
+// StationInfo is the cargo state of a station: input waiting for a processor, output being
+// produced and output that did not fit into load jobs.
+type StationInfo struct {
+	ID          StationID           `json:"id"`
+	Inputs      []string            `json:"inputs"`
+	Outputs     []string            `json:"outputs"`
+	InputBuffer map[CargoType]int   `json:"input_buffer"`
+	Production  []PendingProduction `json:"production"`
+	CargoBuffer map[CargoType]int   `json:"cargo_buffer"`
+	WorldTime   time.Time           `json:"world_time"`
+}
+
+// PendingProduction is a batch of output cargo that is ready once the world clock reaches ReadyAt.
+// Cars are the unloaded cars the output goes into, they wait on Track.
+type PendingProduction struct {
+	CargoType CargoType `json:"cargo_type"`
+	CarCount  int       `json:"car_count"`
+	ReadyAt   time.Time `json:"ready_at"`
+	Cars      []Car     `json:"cars,omitempty"`
+	Track     string    `json:"track,omitempty"`
+}
+
 type LogicStation struct {
 	ID                StationID
 	JobQueue          []*Job
@@ -27,34 +52,46 @@ type LogicStation struct {
 	lastProcIndex     int
 	Processor         []*StationProcessor
 	cargoBuffer       map[CargoType]int
+	cargoBufferCars   map[CargoType][]Car
+	emptyCars         []Car
 	cargoLoadMinCount int
 	cargoLoadMaxCount int
 }
 
-var AllStations = map[StationID]*LogicStation{
-	StationCSW: NewStation(StationCSW, 5, 8),
-	StationCM:  NewStation(StationCM, 6, 12),
-	StationFF:  NewStation(StationFF, 6, 12),
-	StationFM:  NewStation(StationFM, 6, 12),
-	StationFRC: NewStation(StationFRC, 4, 7),
-	StationFRS: NewStation(StationFRS, 4, 7),
-	StationGF:  NewStation(StationGF, 4, 8),
-	StationHB:  NewStation(StationHB, 6, 12),
-	StationHMB: NewStation(StationHMB, 4, 9),
-	StationIME: NewStation(StationIME, 5, 10),
-	StationIMW: NewStation(StationIMW, 5, 10),
-	StationMF:  NewStation(StationMF, 4, 8),
-	StationMB:  NewStation(StationMB, 3, 6),
-	StationOWC: NewStation(StationOWC, 6, 12),
-	StationOWN: NewStation(StationOWN, 6, 12),
-	StationSW:  NewStation(StationSW, 2, 6),
-	StationSM:  NewStation(StationSM, 2, 6),
+var AllStations = newStationMap()
+
+func newStationMap() map[StationID]*LogicStation {
+	return map[StationID]*LogicStation{
+		StationCSW: NewStation(StationCSW, 5, 8),
+		StationCM:  NewStation(StationCM, 6, 12),
+		StationFF:  NewStation(StationFF, 6, 12),
+		StationFM:  NewStation(StationFM, 6, 12),
+		StationFRC: NewStation(StationFRC, 4, 7),
+		StationFRS: NewStation(StationFRS, 4, 7),
+		StationGF:  NewStation(StationGF, 4, 8),
+		StationHB:  NewStation(StationHB, 6, 12),
+		StationHMB: NewStation(StationHMB, 4, 9),
+		StationIME: NewStation(StationIME, 5, 10),
+		StationIMW: NewStation(StationIMW, 5, 10),
+		StationMF:  NewStation(StationMF, 4, 8),
+		StationMB:  NewStation(StationMB, 3, 6),
+		StationOWC: NewStation(StationOWC, 6, 12),
+		StationOWN: NewStation(StationOWN, 6, 12),
+		StationSW:  NewStation(StationSW, 2, 6),
+		StationSM:  NewStation(StationSM, 2, 6),
+	}
 }
 
 func Setup() {
 	// CSW
 	// Generative output
 	AllStations[StationCSW].AddProcessor(NewProcessor(map[CargoType]int{None: 1}, ScrapMetal, StationSM))
+	// Consumers
+	AllStations[StationCSW].AddProcessor(NewSinkProcessor(
+		CannedFood, CatFood, MeatProducts, Medicine, Diesel, Furniture, NewCars, ImportedNewCars,
+		ClothingNeoGamma, ClothingNovae,
+		ToolsIskar, ToolsBrohm, ToolsAAG, ToolsNovae, ToolsTraeg,
+	))
 
 	// CM
 	// Generative output
@@ -63,10 +100,10 @@ func Setup() {
 	// FF
 	// Transformative processors
 	AllStations[StationFF].AddProcessor(NewProcessor(map[CargoType]int{Wheat: 1}, Alcohol, StationHB))
-	AllStations[StationFF].AddProcessor(NewProcessor(map[CargoType]int{Pigs: 1}, CannedFood, StationHB, StationCSW))
+	AllStations[StationFF].AddProcessor(NewProcessor(map[CargoType]int{Pigs: 1}, CannedFood, StationHB, StationCSW, StationMB))
 	AllStations[StationFF].AddProcessor(NewProcessor(map[CargoType]int{Chickens: 1}, CatFood, StationHB, StationCSW))
-	AllStations[StationFF].AddProcessor(NewProcessor(map[CargoType]int{Cows: 2}, MeatProducts, StationCSW))
-	AllStations[StationFF].AddProcessor(NewProcessor(map[CargoType]int{Sheep: 2}, MeatProducts, StationCSW))
+	AllStations[StationFF].AddProcessor(NewProcessor(map[CargoType]int{Cows: 2}, MeatProducts, StationCSW, StationMB))
+	AllStations[StationFF].AddProcessor(NewProcessor(map[CargoType]int{Sheep: 2}, MeatProducts, StationCSW, StationMB))
 
 	// FM
 	// Generative output
@@ -91,19 +128,26 @@ func Setup() {
 	AllStations[StationGF].AddProcessor(NewProcessor(map[CargoType]int{SteelBillets: 1}, ToolsAAG, StationMF, StationCSW))
 	AllStations[StationGF].AddProcessor(NewProcessor(map[CargoType]int{SteelBillets: 1}, ToolsNovae, StationMF, StationCSW))
 	AllStations[StationGF].AddProcessor(NewProcessor(map[CargoType]int{SteelBillets: 1}, ToolsTraeg, StationMF, StationCSW))
+	AllStations[StationGF].AddProcessor(NewProcessor(map[CargoType]int{Boards: 1, Plywood: 1}, Furniture, StationCSW, StationHMB))
 
 	// HB
 	// Generative output
 	AllStations[StationHB].AddProcessor(NewProcessor(map[CargoType]int{None: 1}, Ammonia, StationFF))
 	AllStations[StationHB].AddProcessor(NewProcessor(map[CargoType]int{None: 1}, ImportedNewCars, StationCSW))
 	AllStations[StationHB].AddProcessor(NewProcessor(map[CargoType]int{None: 1}, ClothingNeoGamma, StationCSW))
-	AllStations[StationHB].AddProcessor(NewProcessor(map[CargoType]int{None: 1}, Medicine, StationCSW))
+	AllStations[StationHB].AddProcessor(NewProcessor(map[CargoType]int{None: 1}, Medicine, StationCSW, StationHMB, StationMB))
 	AllStations[StationHB].AddProcessor(NewProcessor(map[CargoType]int{None: 1}, ClothingNovae, StationCSW))
 	AllStations[StationHB].AddProcessor(NewProcessor(map[CargoType]int{None: 1}, Acetylene, StationGF))
 	AllStations[StationHB].AddProcessor(NewProcessor(map[CargoType]int{None: 1}, CryoHydrogen, StationGF))
 	AllStations[StationHB].AddProcessor(NewProcessor(map[CargoType]int{None: 1}, CryoOxygen, StationGF))
 	AllStations[StationHB].AddProcessor(NewProcessor(map[CargoType]int{None: 1}, Methane, StationGF))
-	AllStations[StationHB].AddProcessor(NewProcessor(map[CargoType]int{CrudeOil: 1}, Diesel, StationCSW))
+	AllStations[StationHB].AddProcessor(NewProcessor(map[CargoType]int{CrudeOil: 1}, Diesel, StationCSW, StationMB))
+	// Export
+	AllStations[StationHB].AddProcessor(NewSinkProcessor(Alcohol, CannedFood, CatFood, NewCars))
+
+	// HMB
+	// Consumers
+	AllStations[StationHMB].AddProcessor(NewSinkProcessor(Furniture, Medicine, NewCars))
 
 	// IME
 	// Generative output
@@ -123,9 +167,13 @@ func Setup() {
 	AllStations[StationMF].AddProcessor(NewProcessor(
 		map[CargoType]int{SteelBillets: 1, SteelSlabs: 1},
 		NewCars,
-		StationCSW, StationHB,
+		StationCSW, StationHB, StationHMB,
 	))
 
+	// MB
+	// Consumers
+	AllStations[StationMB].AddProcessor(NewSinkProcessor(Medicine, CannedFood, MeatProducts, Diesel))
+
 	// OWC
 	// Generative output
 	AllStations[StationOWC].AddProcessor(NewProcessor(map[CargoType]int{None: 1}, CrudeOil, StationHB))
@@ -145,16 +193,16 @@ func Setup() {
 	AllStations[StationSM].AddProcessor(NewProcessor(map[CargoType]int{Coal: 1, IronOre: 2}, SteelBillets, StationGF, StationMF))
 
 	// make sure we always spawn the later end of the job chain before any earlier stages
-	for _, logicStation := range AllStations {
+	for _, logicStation := range SortedStations() {
 		logicStation.ValidateJobs(ShuntingUnloadJobType)
 	}
-	for _, logicStation := range AllStations {
+	for _, logicStation := range SortedStations() {
 		logicStation.ValidateJobs(FreightJobType)
 	}
-	for _, logicStation := range AllStations {
+	for _, logicStation := range SortedStations() {
 		logicStation.ValidateJobs(ShuntingLoadJobType)
 	}
-	for _, logicStation := range AllStations {
+	for _, logicStation := range SortedStations() {
 		logicStation.ValidateJobs(LogisticHaulJobType)
 	}
 }
@@ -170,15 +218,28 @@ func NewProcessor(in map[CargoType]int, out CargoType, targetStation ...StationI
 	for cType := range in {
 		proc.allowedInput = append(proc.allowedInput, cType)
 	}
+	slices.Sort(proc.allowedInput)
 
 	return proc
 }
 
+// NewSinkProcessor creates a processor that consumes the given cargo without producing
+// anything, e.g. finished goods delivered to cities or the military base.
+func NewSinkProcessor(in ...CargoType) *StationProcessor {
+	blueprint := make(map[CargoType]int, len(in))
+	for _, cType := range in {
+		blueprint[cType] = 1
+	}
+
+	return NewProcessor(blueprint, None)
+}
+
 func NewStation(id StationID, minCarCount, maxCarCount int) *LogicStation {
 	lStation := &LogicStation{
 		ID:                id,
 		JobQueue:          []*Job{},
 		cargoBuffer:       make(map[CargoType]int),
+		cargoBufferCars:   make(map[CargoType][]Car),
 		Processor:         []*StationProcessor{},
 		cargoLoadMinCount: minCarCount,
 		cargoLoadMaxCount: maxCarCount,
@@ -209,12 +270,42 @@ func (s *LogicStation) GetJob(jobID string) (*Job, error) {
 	return nil, fmt.Errorf("job %s not found", jobID)
 }
 
+// jobOrigin describes cars already standing at the station that a new job takes over
+type jobOrigin struct {
+	chainID string
+	track   string
+	cars    []Car
+}
+
 func (s *LogicStation) AddJob(
 	targetStation StationID,
 	jobType JobType,
 	carCount int,
 	cargoType CargoType,
-	wage int,
+	reason string,
+) *Job {
+	return s.addJob(targetStation, jobType, carCount, cargoType, nil, reason)
+}
+
+// addFollowUpJob continues the chain of prev with the same cars, starting on the track prev
+// delivered them to.
+func (s *LogicStation) addFollowUpJob(prev *Job, targetStation StationID, jobType JobType) *Job {
+	origin := &jobOrigin{
+		chainID: prev.ChainID,
+		track:   prev.TargetTrack,
+		cars:    prev.Cars,
+	}
+
+	return s.addJob(targetStation, jobType, prev.CarCount, prev.CargoType, origin, fmt.Sprintf("follow-up of %s", prev.ID))
+}
+
+func (s *LogicStation) addJob(
+	targetStation StationID,
+	jobType JobType,
+	carCount int,
+	cargoType CargoType,
+	origin *jobOrigin,
+	reason string,
 ) *Job {
 	var (
 		startTrackType  TrackTypeID
@@ -243,10 +334,24 @@ func (s *LogicStation) AddJob(
 		JobType:             jobType,
 		CarCount:            carCount,
 		CargoType:           cargoType,
-		Wage:                wage,
 		startTrackType:      startTrackType,
 		targetTrackType:     targetTrackType,
 	}
+	j.ChainID = j.ID
+	if origin != nil {
+		if origin.chainID != "" {
+			j.ChainID = origin.chainID
+		}
+		j.Cars = slices.Clone(origin.cars)
+		if origin.track != "" {
+			j.StartingTrack = origin.track
+			j.fixedStartTrack = true
+		}
+	}
+	j.resizeCars()
+	j.recalcWage()
+	j.updateLicenseRequired()
+	j.addHistory(JobEventCreated, "", reason)
 
 	logrus.WithFields(logrus.Fields{
 		"job_id":     j.ID,
@@ -257,13 +362,25 @@ func (s *LogicStation) AddJob(
 	return j
 }
 
+// setCarCount changes the number of cars and recalculates the wage
+func (j *Job) setCarCount(carCount int) {
+	j.CarCount = carCount
+	j.resizeCars()
+	j.recalcWage()
+	j.updateLicenseRequired()
+}
+
+func (j *Job) recalcWage() {
+	j.Wage = calcWage(j.JobType, j.CargoType, j.CarCount, j.StartingStationName, j.TargetStationName)
+	j.WageMultiplier = wageMultiplier(j.CargoType, j.StartingStationName, j.TargetStationName)
+}
+
 func (s *LogicStation) ProcessJob(j *Job) []*Job {
 	switch j.JobType {
 	case ShuntingUnloadJobType:
-		newJob := s.procShuntingUnload(j)
-		if newJob != nil {
-			return []*Job{newJob}
-		}
+		return s.procShuntingUnload(j)
+	case LogisticHaulJobType:
+		return s.procLogisticHaul(j)
 	case ShuntingLoadJobType:
 		return s.procShuntingLoad(j)
 	case FreightJobType:
@@ -277,40 +394,73 @@ func (s *LogicStation) ProcessJob(j *Job) []*Job {
 }
 
 func (s *LogicStation) procFreight(j *Job) *Job {
-	return s.AddJob(s.ID, ShuntingUnloadJobType, j.CarCount, j.CargoType, j.CarCount*500)
+	return s.addFollowUpJob(j, s.ID, ShuntingUnloadJobType)
 }
 
-func (s *LogicStation) procShuntingUnload(j *Job) *Job {
+// procShuntingUnload feeds the cargo to a processor. Unloaded cars that fit the output stay
+// with the started batch on the unload track, the others wait at the station as empties.
+// Production that is due right away, i.e. with a production time of 0, already ends up in
+// a load job.
+func (s *LogicStation) procShuntingUnload(j *Job) []*Job {
+	unused := s.unloadToProcessor(j)
+	s.emptyCars = append(s.emptyCars, unused...)
+
+	newJobs, _ := s.completeProduction()
+	return append(newJobs, s.dispatchEmptyCars()...)
+}
+
+// unloadToProcessor returns the cars of the job that did not go into a batch
+func (s *LogicStation) unloadToProcessor(j *Job) []Car {
 	for i := 0; i < len(s.Processor); i++ {
 		index := (s.lastProcIndex + i) % len(s.Processor)
 		proc := s.Processor[index]
 
 		if proc.isAllowed(j.CargoType) {
-			if proc.output == None {
-				return nil
+			if proc.isSink() {
+				// the cargo is used up for good, only the empty cars stay behind
+				return j.Cars
 			}
 
 			proc.buffer[j.CargoType] += j.CarCount
+			return proc.startProduction(&jobOrigin{track: j.TargetTrack, cars: j.Cars})
+		}
+	}
 
-			outCargo := proc.makeOutput()
-			if outCargo > 0 {
-				return s.addCargo(proc.output, outCargo)
+	s.lastProcIndex++
+	return j.Cars
+}
+
+// completeProduction turns every batch that is due on the world clock into load jobs and
+// starts new batches from the buffered input. produced is set when any batch was done, even
+// if its cargo only got merged into a waiting job.
+func (s *LogicStation) completeProduction() (newJobs []*Job, produced bool) {
+	newJobs = make([]*Job, 0)
+	for _, proc := range s.Processor {
+		pending := make([]PendingProduction, 0, len(proc.production))
+		for _, batch := range proc.production {
+			if batch.ReadyAt.After(worldTime) {
+				pending = append(pending, batch)
+				continue
+			}
+
+			produced = true
+			if outputJob := s.addCargo(batch.CargoType, batch.CarCount, batch.origin()); outputJob != nil {
+				newJobs = append(newJobs, outputJob)
 			}
-			return nil
 		}
+		proc.production = pending
+		proc.startProduction(nil)
 	}
 
-	s.lastProcIndex++
-	return nil
+	return newJobs, produced
 }
 
 func (s *LogicStation) procShuntingLoad(j *Job) []*Job {
 	newJobs := make([]*Job, 0)
 	for _, proc := range s.Processor {
 		if proc.output == j.CargoType {
-			targetStation := proc.targetStations[rand.Intn(len(proc.targetStations))]
-			wage := j.CargoType.BaseWage() * j.CarCount
-			newJobs = append(newJobs, s.AddJob(targetStation, FreightJobType, j.CarCount, j.CargoType, wage))
+			targetStation := proc.targetStations[worldRand.Intn(len(proc.targetStations))]
+			newJobs = append(newJobs, s.addFollowUpJob(j, targetStation, FreightJobType))
 
 			if len(proc.allowedInput) <= 0 || (len(proc.allowedInput) == 1 && proc.allowedInput[0] == None) {
 				newJobs = append(newJobs, s.spawnGenerativeLoadJob(proc))
@@ -324,38 +474,79 @@ func (s *LogicStation) procShuntingLoad(j *Job) []*Job {
 }
 
 func (s *LogicStation) spawnGenerativeLoadJob(proc *StationProcessor) *Job {
-	carCount := rand.Intn(s.cargoLoadMaxCount-s.cargoLoadMinCount) + s.cargoLoadMinCount
-	wage := proc.output.BaseWage() * carCount
+	carCount := worldRand.Intn(s.cargoLoadMaxCount-s.cargoLoadMinCount) + s.cargoLoadMinCount
 
-	return s.AddJob(s.ID, ShuntingLoadJobType, carCount, proc.output, wage)
+	// empties standing around are loaded first, only the rest are new cars
+	var origin *jobOrigin
+	if cars := s.takeEmptyCars(proc.output, carCount); len(cars) > 0 {
+		origin = &jobOrigin{cars: cars}
+	}
+
+	return s.addJob(s.ID, ShuntingLoadJobType, carCount, proc.output, origin, "generated by station")
 }
 
-func (s *LogicStation) addCargo(cType CargoType, count int) *Job {
+// addCargo merges processor output into a waiting load job or creates a new one. Only load
+// jobs no client has spawned yet take merged output, a spawned consist never changes. The
+// output goes into the cars of origin first, then into empties waiting at the station. New
+// cars are only made when neither is left. A new job starts on the track of origin.
+func (s *LogicStation) addCargo(cType CargoType, count int, origin *jobOrigin) *Job {
 	for _, j := range s.JobQueue {
-		if j.CargoType == cType {
-			j.CarCount += count
-			if j.CarCount > MAX_CARS_PER_JOB {
-				count = j.CarCount % MAX_CARS_PER_JOB
-				j.CarCount = MAX_CARS_PER_JOB
+		if j.JobType == ShuntingLoadJobType && j.CargoType == cType && !j.jobSpawned && !j.jobReserved && !j.jobActive {
+			j.addHistory(JobEventCargoAdded, "", fmt.Sprintf("merged %d cars of processor output", count))
+			cars := s.outputCars(cType, count, origin)
+
+			// only cars that do not fit into the job anymore go to the buffer
+			overflow := 0
+			newCarCount := j.CarCount + count
+			if newCarCount > economy.MaxCarsPerJob {
+				overflow = newCarCount - economy.MaxCarsPerJob
+				newCarCount = economy.MaxCarsPerJob
 			}
+			added := min(len(cars), newCarCount-j.CarCount)
+			j.Cars = append(j.Cars, cars[:added]...)
+			j.setCarCount(newCarCount)
 
-			// recalc wage
-			j.Wage = j.CargoType.BaseWage() * j.CarCount
-
-			if count > 0 {
-				if _, ok := s.cargoBuffer[cType]; !ok {
-					s.cargoBuffer[cType] = 0
-				}
-
-				s.cargoBuffer[cType] += count
+			if overflow > 0 {
+				s.cargoBuffer[cType] += overflow
+				s.cargoBufferCars[cType] = append(s.cargoBufferCars[cType], cars[added:]...)
 			}
 
 			return nil
 		}
 	}
 
-	newWage := cType.BaseWage() * count
-	return s.AddJob(s.ID, ShuntingLoadJobType, count, cType, newWage)
+	var newOrigin *jobOrigin
+	if cars := s.outputCars(cType, count, origin); len(cars) > 0 {
+		newOrigin = &jobOrigin{cars: cars}
+		if origin != nil {
+			newOrigin.track = origin.track
+		}
+	}
+
+	return s.addJob(s.ID, ShuntingLoadJobType, count, cType, newOrigin, "processor output")
+}
+
+// outputCars picks up to count cars for processor output, the cars of origin that fit the
+// cargo first, then empties waiting at the station. Cars of origin left over become empties.
+func (s *LogicStation) outputCars(cType CargoType, count int, origin *jobOrigin) []Car {
+	cars := make([]Car, 0, count)
+	if origin != nil {
+		cars = reusableCars(origin.cars, cType, count)
+		s.emptyCars = append(s.emptyCars, slices.DeleteFunc(slices.Clone(origin.cars), func(car Car) bool {
+			return slices.Contains(cars, car)
+		})...)
+	}
+
+	return append(cars, s.takeEmptyCars(cType, count-len(cars))...)
+}
+
+// takeBufferedCars removes up to count cars loaded with buffered cargo from the station
+func (s *LogicStation) takeBufferedCars(cType CargoType, count int) []Car {
+	buffered := s.cargoBufferCars[cType]
+	taken := slices.Clone(buffered[:min(count, len(buffered))])
+	s.cargoBufferCars[cType] = buffered[len(taken):]
+
+	return taken
 }
 
 func (s *LogicStation) trySpawnJob(j *Job) (changed, newSpawn, despawn bool) {
@@ -372,7 +563,12 @@ func (s *LogicStation) trySpawnJob(j *Job) (changed, newSpawn, despawn bool) {
 
 	var startTrackPtr *string
 	if !j.jobSpawned {
-		startTrackPtr = s.GetFreeTrackName(j.startTrackType)
+		if j.fixedStartTrack {
+			// the cars already stand there
+			startTrackPtr = &j.StartingTrack
+		} else {
+			startTrackPtr = s.GetFreeTrackName(j.startTrackType)
+		}
 		if startTrackPtr == nil {
 			return
 		}
@@ -382,6 +578,7 @@ func (s *LogicStation) trySpawnJob(j *Job) (changed, newSpawn, despawn bool) {
 	targetTrackPtr := targetLogicStation.GetFreeTrackName(j.targetTrackType)
 	if targetTrackPtr == nil {
 		if j.jobSpawned {
+			j.addHistory(JobEventUnspawned, "", fmt.Sprintf("no free target track at %s", j.TargetStationName))
 			j.jobSpawned = false
 			changed = true
 			despawn = true
@@ -392,12 +589,44 @@ func (s *LogicStation) trySpawnJob(j *Job) (changed, newSpawn, despawn bool) {
 	}
 
 	if j.TargetTrack != *targetTrackPtr {
+		if j.jobSpawned {
+			j.addHistory(JobEventTrackChanged, "", fmt.Sprintf("target track %s -> %s", j.TargetTrack, *targetTrackPtr))
+		}
 		changed = true
 	}
 	j.TargetTrack = *targetTrackPtr
 
+	if j.usesLoadingTrack() {
+		// storage -> loading -> output, the cars get loaded on the way
+		loadingTrackPtr := s.GetFreeTrackName(LoadingTrackType)
+		if loadingTrackPtr == nil {
+			if j.jobSpawned {
+				j.addHistory(JobEventUnspawned, "", fmt.Sprintf("no free loading track at %s", s.ID))
+				j.jobSpawned = false
+				changed = true
+				despawn = true
+				return
+			}
+
+			return
+		}
+
+		if j.LoadingTrack != *loadingTrackPtr {
+			if j.jobSpawned {
+				j.addHistory(JobEventTrackChanged, "", fmt.Sprintf("loading track %s -> %s", j.LoadingTrack, *loadingTrackPtr))
+			}
+			changed = true
+		}
+		j.LoadingTrack = *loadingTrackPtr
+	}
+
 	if !j.jobSpawned {
 		j.StartingTrack = *startTrackPtr
+		if j.LoadingTrack != "" {
+			j.addHistory(JobEventSpawned, "", fmt.Sprintf("from %s via %s to %s", j.StartingTrack, j.LoadingTrack, j.TargetTrack))
+		} else {
+			j.addHistory(JobEventSpawned, "", fmt.Sprintf("from %s to %s", j.StartingTrack, j.TargetTrack))
+		}
 		j.jobSpawned = true
 		changed = true
 		newSpawn = true
@@ -448,6 +677,56 @@ func (s *LogicStation) GetJobID(t JobType) string {
 	return fmt.Sprintf("%s-%s-%0d", s.ID, t.AsID(), s.lastJobNum)
 }
 
+func (s *LogicStation) CountSpawnedJobs() int {
+	count := 0
+	for _, j := range s.JobQueue {
+		if j.jobSpawned {
+			count++
+		}
+	}
+
+	return count
+}
+
+// GetCargoBuffer returns the cargo that did not fit into existing load jobs anymore.
+func (s *LogicStation) GetCargoBuffer() map[CargoType]int {
+	return maps.Clone(s.cargoBuffer)
+}
+
+// GetStationInfo returns the inputs, pending production and buffers of the station.
+func GetStationInfo(id StationID) (StationInfo, error) {
+	jobLock.Lock()
+	defer jobLock.Unlock()
+
+	s := GetStation(id)
+	if s == nil {
+		return StationInfo{}, fmt.Errorf("station %s not found", id)
+	}
+
+	info := StationInfo{
+		ID:          s.ID,
+		Inputs:      s.AllInputs(),
+		Outputs:     s.AllOutputs(),
+		InputBuffer: make(map[CargoType]int),
+		Production:  make([]PendingProduction, 0),
+		CargoBuffer: s.GetCargoBuffer(),
+		WorldTime:   worldTime,
+	}
+	for _, proc := range s.Processor {
+		for cType, count := range proc.buffer {
+			if count > 0 {
+				info.InputBuffer[cType] += count
+			}
+		}
+		info.Production = append(info.Production, proc.production...)
+	}
+	slices.SortStableFunc(info.Production, func(a, b PendingProduction) int {
+		return a.ReadyAt.Compare(b.ReadyAt)
+	})
+
+	return info, nil
+}
+
 func (s *LogicStation) AllInputs() []string {
 	str := []string{}
 	for _, proc := range s.Processor {
@@ -492,10 +771,61 @@ func (proc *StationProcessor) isAllowed(cType CargoType) bool {
 	return false
 }
 
-func (proc *StationProcessor) makeOutput() int {
+func (proc *StationProcessor) isSink() bool {
+	return proc.output == None
+}
+
+// startProduction consumes buffered input into a new batch as far as the production
+// capacity allows. The batch is ready after the configured production time. The batch keeps
+// the cars of origin that fit the output, the others are returned.
+func (proc *StationProcessor) startProduction(origin *jobOrigin) (unused []Car) {
+	if origin != nil {
+		unused = origin.cars
+	}
+
+	inProduction := 0
+	for _, batch := range proc.production {
+		inProduction += batch.CarCount
+	}
+
+	outCount := proc.makeOutput(economy.ProductionCapacity - inProduction)
+	if outCount <= 0 {
+		return unused
+	}
+
+	batch := PendingProduction{
+		CargoType: proc.output,
+		CarCount:  outCount,
+		ReadyAt:   worldTime.Add(economy.ProductionDuration()),
+	}
+	if origin != nil {
+		batch.Cars = reusableCars(origin.cars, proc.output, outCount)
+		if len(batch.Cars) > 0 {
+			batch.Track = origin.track
+		}
+		unused = slices.DeleteFunc(slices.Clone(origin.cars), func(car Car) bool {
+			return slices.Contains(batch.Cars, car)
+		})
+	}
+	proc.production = append(proc.production, batch)
+
+	return unused
+}
+
+// origin returns the cars the batch output goes into, nil if it has none
+func (batch PendingProduction) origin() *jobOrigin {
+	if len(batch.Cars) == 0 {
+		return nil
+	}
+
+	return &jobOrigin{track: batch.Track, cars: batch.Cars}
+}
+
+// makeOutput consumes input for at most limit output cars and returns the number of cars
+func (proc *StationProcessor) makeOutput(limit int) int {
 	goon := true
 	outCounter := 0
-	for goon {
+	for goon && outCounter < limit {
 		for _, cargoType := range proc.allowedInput {
 			if proc.buffer[cargoType] < proc.blueprint[cargoType] {
 				goon = false
@@ -0,0 +1,54 @@
+package sharedjob
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSinkConsumesCargo(t *testing.T) {
+	ResetWorld()
+	SeedWorld(3)
+	Setup()
+
+	hmb := AllStations[StationHMB]
+	unload := hmb.AddJob(StationHMB, ShuntingUnloadJobType, 3, Medicine, "test delivery")
+
+	for _, newJob := range hmb.ProcessJob(unload) {
+		if newJob.JobType == ShuntingLoadJobType {
+			t.Errorf("sink produced load job %s with %s", newJob.ID, newJob.CargoType)
+		}
+	}
+	if len(hmb.emptyCars) != 3 {
+		t.Errorf("expected the 3 unloaded cars to stay as empties, got %d", len(hmb.emptyCars))
+	}
+	if len(hmb.AllOutputs()) != 0 {
+		t.Errorf("sink station must not have outputs, got %v", hmb.AllOutputs())
+	}
+}
+
+func TestOutputNeverChangesSpawnedJobs(t *testing.T) {
+	ResetWorld()
+	SeedWorld(3)
+	Setup()
+
+	cm := AllStations[StationCM]
+	var spawned *Job
+	for _, j := range cm.JobQueue {
+		if j.JobType == ShuntingLoadJobType && j.jobSpawned {
+			spawned = j
+			break
+		}
+	}
+	if spawned == nil {
+		t.Fatal("expected a spawned load job at CM")
+	}
+	cars := fmt.Sprint(spawned.Cars)
+
+	newJob := cm.addCargo(spawned.CargoType, 2, nil)
+	if newJob == nil || newJob == spawned {
+		t.Errorf("expected output for a spawned job to go into a new job, got %v", newJob)
+	}
+	if fmt.Sprint(spawned.Cars) != cars {
+		t.Errorf("expected the spawned consist to stay %s, got %v", cars, spawned.Cars)
+	}
+}
@@ -0,0 +1,51 @@
+package sharedjob
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// timeLimit is the time a player has to finish the job once taken. Hauls get extra time
+// per km of route on top of the base limit of the job type.
+func (j *Job) timeLimit() time.Duration {
+	minutes := float64(economy.TimeLimitMinutes[j.JobType])
+	if j.JobType == FreightJobType || j.JobType == LogisticHaulJobType {
+		minutes += StationDistance(j.StartingStationName, j.TargetStationName) * economy.TimeLimitMinutesPerKm
+	}
+
+	return time.Duration(minutes * float64(time.Minute))
+}
+
+// minBonusElapsedShare is the share of the time limit that has to pass before finishing early
+// pays a bonus. Nobody drives a job in less, a faster finish is not a real delivery.
+const minBonusElapsedShare = 0.25
+
+// deliveryAdjustment returns the bonus (positive) or penalty (negative) for finishing at the
+// given time. Finishing once minBonusElapsedShare of the time limit has passed pays the
+// largest early bonus, the bonus shrinks to zero at the deadline. Finishing sooner pays none.
+// Past the deadline the penalty grows until the job is a full time limit overdue.
+func (j *Job) deliveryAdjustment(finishedAt time.Time) (int, string) {
+	if j.Deadline == nil {
+		return 0, ""
+	}
+
+	limit := j.Deadline.Sub(j.takenAt)
+	if limit <= 0 {
+		return 0, ""
+	}
+
+	remaining := j.Deadline.Sub(finishedAt)
+	if elapsed := finishedAt.Sub(j.takenAt); float64(elapsed) < float64(limit)*minBonusElapsedShare {
+		return 0, fmt.Sprintf("finished %s after taking, too soon for a bonus", elapsed.Round(time.Second))
+	}
+	if remaining >= 0 {
+		share := float64(remaining) / float64(limit)
+		bonus := int(float64(j.Wage) * economy.EarlyBonus * share)
+		return bonus, fmt.Sprintf("early by %s", remaining.Round(time.Second))
+	}
+
+	share := math.Min(1, float64(-remaining)/float64(limit))
+	penalty := int(float64(j.Wage) * economy.LatePenalty * share)
+	return -penalty, fmt.Sprintf("late by %s", (-remaining).Round(time.Second))
+}
@@ -0,0 +1,30 @@
+package sharedjob
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeliveryAdjustment(t *testing.T) {
+	takenAt := time.Now()
+	deadline := takenAt.Add(10 * time.Minute)
+	j := &Job{Wage: 1000, takenAt: takenAt, Deadline: &deadline}
+
+	if bonus, _ := j.deliveryAdjustment(takenAt.Add(5 * time.Minute)); bonus != 100 {
+		t.Errorf("expected half the early bonus, got %d", bonus)
+	}
+	if bonus, _ := j.deliveryAdjustment(takenAt.Add(time.Second)); bonus != 0 {
+		t.Errorf("expected no bonus for finishing right after taking, got %d", bonus)
+	}
+	if adjustment, _ := j.deliveryAdjustment(deadline); adjustment != 0 {
+		t.Errorf("expected no adjustment at the deadline, got %d", adjustment)
+	}
+	if penalty, _ := j.deliveryAdjustment(deadline.Add(time.Hour)); penalty != -500 {
+		t.Errorf("expected the full late penalty, got %d", penalty)
+	}
+
+	untaken := &Job{Wage: 1000}
+	if adjustment, _ := untaken.deliveryAdjustment(takenAt); adjustment != 0 {
+		t.Errorf("expected no adjustment without a deadline, got %d", adjustment)
+	}
+}
@@ -2,7 +2,6 @@ package sharedjob
 
 import (
 	"fmt"
-	"math/rand"
 )
 
 type (
@@ -158,6 +157,17 @@ var StationTrackData = StationTracks{
 			YardID("D"): []TrackNumber{"07"},
 		},
 	},
+	StationHMB: TrackYards{
+		InputTrackType: YardTracks{
+			YardID("A"): []TrackNumber{"01", "02", "03"},
+		},
+		StorageTrackType: YardTracks{
+			YardID("A"): []TrackNumber{"04", "05"},
+			YardID("B"): []TrackNumber{"01"},
+		},
+		OutputTrackType:  YardTracks{},
+		LoadingTrackType: YardTracks{},
+	},
 	StationIME: TrackYards{
 		InputTrackType: YardTracks{
 			YardID("C"): []TrackNumber{"04"},
@@ -204,8 +214,12 @@ var StationTrackData = StationTracks{
 		},
 	},
 	StationMB: TrackYards{
-		InputTrackType:   YardTracks{},
-		StorageTrackType: YardTracks{},
+		InputTrackType: YardTracks{
+			YardID("A"): []TrackNumber{"01", "02"},
+		},
+		StorageTrackType: YardTracks{
+			YardID("A"): []TrackNumber{"03", "04"},
+		},
 		OutputTrackType:  YardTracks{},
 		LoadingTrackType: YardTracks{},
 	},
@@ -299,26 +313,61 @@ func (s *LogicStation) GetFreeTrackName(yardType TrackTypeID) *string {
 	return nil
 }
 
+func (s *LogicStation) GetOccupiedTrackNames(yardType TrackTypeID) []string {
+	occupied := make([]string, 0)
+	for _, trackName := range s.GetAllFullTrackNames(yardType) {
+		if !s.isTrackFree(trackName) {
+			occupied = append(occupied, trackName)
+		}
+	}
+
+	return occupied
+}
+
 func (s *LogicStation) GetRandomTrackName(yardType TrackTypeID) string {
 	trackNames := s.GetAllFullTrackNames(yardType)
 	if len(trackNames) == 0 {
 		return ""
 	}
 
-	index := rand.Intn(len(trackNames))
+	index := worldRand.Intn(len(trackNames))
 	return trackNames[index]
 }
 
+// usesLoadingTrack reports whether the job routes its cars through a loading track of
+// its starting station. Only shunting load jobs do, and only where the station has one.
+func (j *Job) usesLoadingTrack() bool {
+	if j.JobType != ShuntingLoadJobType {
+		return false
+	}
+
+	return len(j.GetStartStation().GetAllFullTrackNames(LoadingTrackType)) > 0
+}
+
 func (s *LogicStation) isTrackFree(trackName string) bool {
 	for _, station := range AllStations {
 		for _, j := range station.JobQueue {
-			if j.jobSpawned && j.StartingTrack == trackName {
+			// follow-up jobs hold their start track even before they spawn
+			if (j.jobSpawned || j.fixedStartTrack) && j.StartingTrack == trackName {
 				return false
 			}
 
 			if j.jobActive && j.TargetTrack == trackName {
 				return false
 			}
+
+			if j.jobActive && j.LoadingTrack == trackName {
+				return false
+			}
+		}
+
+		// unloaded cars wait on their track until the output is loaded into them
+		for _, proc := range station.Processor {
+			for _, batch := range proc.production {
+				if batch.Track == trackName {
+					return false
+				}
+			}
 		}
 	}
 
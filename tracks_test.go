@@ -0,0 +1,69 @@
+package sharedjob
+
+import "testing"
+
+func TestShuntingLoadHoldsLoadingTrack(t *testing.T) {
+	ResetWorld()
+	SeedWorld(5)
+	Setup()
+	GrantLicenses("test", AllLicenses()...)
+
+	for _, station := range SortedStations() {
+		for _, j := range station.JobQueue {
+			if j.JobType != ShuntingLoadJobType || !j.IsSpawned() {
+				continue
+			}
+			if j.LoadingTrack == "" {
+				t.Fatalf("spawned load job %s at %s has no loading track", j.ID, station.ID)
+			}
+
+			progressCh := make(chan ProgressMessage, 100)
+			ReserveJob("test", j.ID)
+			if ok, _, _, _ := TakeJob("test", j.ID, progressCh); !ok {
+				t.Fatalf("unable to take %s", j.ID)
+			}
+
+			if station.isTrackFree(j.LoadingTrack) {
+				t.Errorf("loading track %s still free while %s is active", j.LoadingTrack, j.ID)
+			}
+			for _, other := range station.JobQueue {
+				if other != j && other.IsSpawned() && other.LoadingTrack == j.LoadingTrack {
+					t.Errorf("%s shares loading track %s with active %s", other.ID, j.LoadingTrack, j.ID)
+				}
+			}
+
+			FinishJob("test", j.ID, FinishReport{}, progressCh)
+			if !station.isTrackFree(j.LoadingTrack) {
+				t.Errorf("loading track %s not released after finishing %s", j.LoadingTrack, j.ID)
+			}
+			return
+		}
+	}
+	t.Fatal("no spawned shunting load job")
+}
+
+func TestPendingProductionHoldsUnloadTrack(t *testing.T) {
+	ResetWorld()
+	SeedWorld(5)
+	Setup()
+
+	for _, station := range AllStations {
+		station.JobQueue = nil
+	}
+	hb := AllStations[StationHB]
+	track := hb.GetFreeTrackName(StorageTrackType)
+	if track == nil {
+		t.Fatal("expected a free storage track at HB")
+	}
+
+	unload := hb.AddJob(StationHB, ShuntingUnloadJobType, 4, CrudeOil, "test delivery")
+	unload.TargetTrack = *track
+	hb.ProcessJob(unload)
+
+	if hb.isTrackFree(*track) {
+		t.Errorf("expected %s to be held while its cars wait for production", *track)
+	}
+	if free := hb.GetFreeTrackName(StorageTrackType); free != nil && *free == *track {
+		t.Errorf("expected %s not to be handed out while a batch is pending", *track)
+	}
+}
@@ -13,11 +13,13 @@ const (
 	connections__0 = `<!DOCTYPE html><html lang="en">`
 	connections__1 = `<head>`
 	connections__2 = `<title>Our little derail valley - `
-	connections__3 = `</title><meta name="viewport" content="width=device-width, initial-scale=1"/><script src="https://unpkg.com/htmx.org@1.9.6"></script><style>      @import "https://unpkg.com/bulma@0.9.4/css/bulma.min.css";</style></head><body><nav class="navbar" role="navigation"><div class="navbar-menu"><div class="navbar-start"><a class="navbar-item" href="/ui/jobs">Jobs</a><a class="navbar-item" href="/ui/stations">Stations</a><a class="navbar-item" href="/ui/connections">Connected players</a></div></div></nav><section class="section"><div class="container is-fullhd"><h1 class="title">`
-	connections__4 = `</h1><p>List of all connected player</p><table class="table is-fullwidth is-striped"><thead><tr><th>Name</th></tr></thead><tbody id="player-table">`
+	connections__3 = `</title><meta name="viewport" content="width=device-width, initial-scale=1"/><script src="https://unpkg.com/htmx.org@1.9.6"></script><style>      @import "https://unpkg.com/bulma@0.9.4/css/bulma.min.css";</style></head><body><nav class="navbar" role="navigation"><div class="navbar-menu"><div class="navbar-start"><a class="navbar-item" href="/ui/jobs">Jobs</a><a class="navbar-item" href="/ui/stations">Stations</a><a class="navbar-item" href="/ui/connections">Connected players</a><a class="navbar-item" href="/ui/leaderboard">Leaderboard</a></div></div></nav><section class="section"><div class="container is-fullhd"><h1 class="title">`
+	connections__4 = `</h1><p>List of all connected player</p><table class="table is-fullwidth is-striped"><thead><tr><th>Name</th><th></th></tr></thead><tbody id="player-table">`
 	connections__5 = `</tbody></table><div id="modal-target"></div></div></section></body></html>`
 	connections__6 = `<tr><td>`
 	connections__7 = `</td></tr>`
+	connections__8 = `</td><td>`
+	connections__9 = `<span class="tag">Bot</span>`
 )
 
 func ConnectedPlayersView(pageTitle string, players []*sharedjob.Player, wr io.Writer) {
@@ -40,6 +42,12 @@ func ConnectedPlayersView(pageTitle string, players []*sharedjob.Player, wr io.W
 		for _, player := range players {
 			buffer.WriteString(connections__6)
 			WriteEscString(player.Username, buffer)
+			buffer.WriteString(connections__8)
+
+			if player.IsBot() {
+				buffer.WriteString(connections__9)
+
+			}
 			buffer.WriteString(connections__7)
 
 		}
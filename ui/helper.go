@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/devnull-twitch/sharedjob-server"
 )
@@ -18,12 +19,62 @@ func jobFinishURL(jobID string) string {
 	return fmt.Sprintf("/ui/jobs/%s/finish", jobID)
 }
 
-func countSpawnedJobs(station *sharedjob.LogicStation) int {
-	count := 0
-	for _, job := range station.JobQueue {
-		if job.IsSpawned() {
-			count++
-		}
+func jobHistoryURL(jobID string) string {
+	return fmt.Sprintf("/ui/jobs/%s/history", jobID)
+}
+
+func chainURL(chainID string) string {
+	return fmt.Sprintf("/ui/chains/%s", chainID)
+}
+
+func chainRoute(job *sharedjob.Job) string {
+	if job.LoadingTrack != "" {
+		return fmt.Sprintf(
+			"%s %s -> %s -> %s %s",
+			job.StartingStationName, job.StartingTrack, job.LoadingTrack, job.TargetStationName, job.TargetTrack,
+		)
 	}
-	return count
+
+	return fmt.Sprintf("%s %s -> %s %s", job.StartingStationName, job.StartingTrack, job.TargetStationName, job.TargetTrack)
+}
+
+func formatTime(t time.Time) string {
+	return t.Format("2006-01-02 15:04:05")
+}
+
+func cargoName(cargo sharedjob.CargoType) string {
+	if info, ok := cargo.Info(); ok {
+		return info.DisplayName
+	}
+
+	return cargo.String()
+}
+
+func formatMultiplier(multiplier float64) string {
+	return fmt.Sprintf("x%.2f", multiplier)
+}
+
+func leaderboardURL(window sharedjob.LeaderboardWindow) string {
+	return fmt.Sprintf("/ui/leaderboard?window=%s", window)
+}
+
+func leaderboardWindows() []sharedjob.LeaderboardWindow {
+	return []sharedjob.LeaderboardWindow{
+		sharedjob.LeaderboardSession,
+		sharedjob.LeaderboardDay,
+		sharedjob.LeaderboardAllTime,
+	}
+}
+
+func leaderboardCategories() []sharedjob.CargoCategory {
+	return []sharedjob.CargoCategory{
+		sharedjob.CategoryRaw,
+		sharedjob.CategoryEasy,
+		sharedjob.CategoryComplex,
+		sharedjob.CategoryDanger,
+	}
+}
+
+func countSpawnedJobs(station *sharedjob.LogicStation) int {
+	return station.CountSpawnedJobs()
 }
@@ -11,7 +11,7 @@ import (
 const (
 	jobfinish__0 = `<div class="modal is-active"><div class="modal-background"></div><div class="modal-content"><div class="box"><h1 class="title">Finish job `
 	jobfinish__1 = `</h1><form hx-post="`
-	jobfinish__2 = `" hx-target="#modal-target" hx-select-oob="#jobs-table:afterbegin"><div class="field"><label class="label" for="user">Username</label><div class="control"><input class="input" type="text" id="user" name="user" placeholder="Admin"/></div><div class="control"><button class="button is-primary" type="submit">Finish job</button></div></div></form></div></div></div>`
+	jobfinish__2 = `" hx-target="#modal-target" hx-select-oob="#jobs-table:afterbegin"><div class="field"><label class="label" for="user">Username</label><div class="control"><input class="input" type="text" id="user" name="user" placeholder="Admin"/></div><label class="label" for="cargo_loss">Cargo loss (%)</label><div class="control"><input class="input" type="number" id="cargo_loss" name="cargo_loss" min="0" max="100" step="0.1" placeholder="0"/></div><div class="control"><button class="button is-primary" type="submit">Finish job</button></div></div></form></div></div></div>`
 )
 
 func JobPartFinish(jobID string, wr io.Writer) {
@@ -42,6 +42,12 @@ func JobPartUpdates(changedJobs []*sharedjob.Job, newJobs []*sharedjob.Job, dele
 					buffer.WriteString(jobs__20)
 
 				}
+				buffer.WriteString(jobs__17)
+				WriteAll(jobHistoryURL(job.ID), true, buffer)
+				buffer.WriteString(jobs__24)
+				buffer.WriteString(jobs__17)
+				WriteAll(chainURL(job.ChainID), true, buffer)
+				buffer.WriteString(jobs__25)
 				buffer.WriteString(jobs__8)
 				WriteEscString(job.StartingTrack, buffer)
 				buffer.WriteString(jobs__9)
@@ -65,11 +71,13 @@ func JobPartUpdates(changedJobs []*sharedjob.Job, newJobs []*sharedjob.Job, dele
 				buffer.WriteString(jobs__9)
 				WriteEscString(job.GetAssignedUser(), buffer)
 				buffer.WriteString(jobs__9)
-				WriteAll(job.CargoType, true, buffer)
+				WriteEscString(cargoName(job.CargoType), buffer)
 				buffer.WriteString(jobs__9)
 				WriteInt(int64(job.CarCount), buffer)
 				buffer.WriteString(jobs__9)
 				WriteInt(int64(job.Wage), buffer)
+				buffer.WriteString(jobs__9)
+				WriteEscString(formatMultiplier(job.WageMultiplier), buffer)
 				buffer.WriteString(connections__7)
 
 			}
@@ -98,6 +106,12 @@ func JobPartUpdates(changedJobs []*sharedjob.Job, newJobs []*sharedjob.Job, dele
 					buffer.WriteString(jobs__20)
 
 				}
+				buffer.WriteString(jobs__17)
+				WriteAll(jobHistoryURL(job.ID), true, buffer)
+				buffer.WriteString(jobs__24)
+				buffer.WriteString(jobs__17)
+				WriteAll(chainURL(job.ChainID), true, buffer)
+				buffer.WriteString(jobs__25)
 				buffer.WriteString(jobs__8)
 				WriteEscString(job.StartingTrack, buffer)
 				buffer.WriteString(jobs__9)
@@ -121,11 +135,13 @@ func JobPartUpdates(changedJobs []*sharedjob.Job, newJobs []*sharedjob.Job, dele
 				buffer.WriteString(jobs__9)
 				WriteEscString(job.GetAssignedUser(), buffer)
 				buffer.WriteString(jobs__9)
-				WriteAll(job.CargoType, true, buffer)
+				WriteEscString(cargoName(job.CargoType), buffer)
 				buffer.WriteString(jobs__9)
 				WriteInt(int64(job.CarCount), buffer)
 				buffer.WriteString(jobs__9)
 				WriteInt(int64(job.Wage), buffer)
+				buffer.WriteString(jobs__9)
+				WriteEscString(formatMultiplier(job.WageMultiplier), buffer)
 				buffer.WriteString(connections__7)
 
 			}
@@ -10,7 +10,7 @@ import (
 )
 
 const (
-	jobs__4  = `</h1><p>List of all jobs</p><table class="table is-fullwidth is-striped"><thead><tr><th></th><th>Job ID </th><th>Start Track</th><th>Target Track</th><th>Status </th><th>Assigned user</th><th>Cargo </th><th>No. of cars </th><th>Wage </th></tr></thead><tbody id="jobs-table">`
+	jobs__4  = `</h1><p>List of all jobs</p><table class="table is-fullwidth is-striped"><thead><tr><th></th><th>Job ID </th><th>Start Track</th><th>Target Track</th><th>Status </th><th>Assigned user</th><th>Cargo </th><th>No. of cars </th><th>Wage </th><th>Demand </th></tr></thead><tbody id="jobs-table">`
 	jobs__6  = `<tr id="`
 	jobs__7  = `"><td><div class="buttons are-small">`
 	jobs__8  = `</div></td><td>`
@@ -21,6 +21,8 @@ const (
 	jobs__21 = `<span class="tag">Reserved</span>`
 	jobs__22 = `<span class="tag">Active</span>`
 	jobs__23 = `<span class="tag">Spawned </span>`
+	jobs__24 = `" hx-target="#modal-target">History</button>`
+	jobs__25 = `" hx-target="#modal-target">Chain</button>`
 )
 
 func JobsView(pageTitle string, stations map[sharedjob.StationID]*sharedjob.LogicStation, wr io.Writer) {
@@ -58,6 +60,12 @@ func JobsView(pageTitle string, stations map[sharedjob.StationID]*sharedjob.Logi
 					buffer.WriteString(jobs__20)
 
 				}
+				buffer.WriteString(jobs__17)
+				WriteAll(jobHistoryURL(job.ID), true, buffer)
+				buffer.WriteString(jobs__24)
+				buffer.WriteString(jobs__17)
+				WriteAll(chainURL(job.ChainID), true, buffer)
+				buffer.WriteString(jobs__25)
 				buffer.WriteString(jobs__8)
 				WriteEscString(job.StartingTrack, buffer)
 				buffer.WriteString(jobs__9)
@@ -81,11 +89,13 @@ func JobsView(pageTitle string, stations map[sharedjob.StationID]*sharedjob.Logi
 				buffer.WriteString(jobs__9)
 				WriteEscString(job.GetAssignedUser(), buffer)
 				buffer.WriteString(jobs__9)
-				WriteAll(job.CargoType, true, buffer)
+				WriteEscString(cargoName(job.CargoType), buffer)
 				buffer.WriteString(jobs__9)
 				WriteInt(int64(job.CarCount), buffer)
 				buffer.WriteString(jobs__9)
 				WriteInt(int64(job.Wage), buffer)
+				buffer.WriteString(jobs__9)
+				WriteEscString(formatMultiplier(job.WageMultiplier), buffer)
 				buffer.WriteString(connections__7)
 
 			}
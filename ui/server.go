@@ -3,6 +3,7 @@ package ui
 import (
 	"net/http"
 	"slices"
+	"strconv"
 
 	"github.com/devnull-twitch/sharedjob-server"
 	"github.com/gin-gonic/gin"
@@ -80,11 +81,28 @@ func AddUIHandlers(r *gin.Engine, processorCh chan<- sharedjob.ProgressMessage)
 				return
 			}
 
-			ok, unspawnedJobs, spawnedJobs, changedJobs, newJobs := sharedjob.FinishJob(
+			report := sharedjob.FinishReport{}
+			if cargoLoss := c.PostForm("cargo_loss"); cargoLoss != "" {
+				loss, err := strconv.ParseFloat(cargoLoss, 64)
+				report.CargoLoss = loss
+				if err != nil {
+					uiLog.WithError(err).Warn("invalid cargo loss")
+					c.Status(http.StatusBadRequest)
+					return
+				}
+			}
+
+			ok, unspawnedJobs, spawnedJobs, changedJobs, newJobs, err := sharedjob.FinishJob(
 				assigneUser,
 				jobID,
+				report,
 				processorCh,
 			)
+			if err != nil {
+				uiLog.WithError(err).Warn("invalid finish report")
+				c.Status(http.StatusBadRequest)
+				return
+			}
 			if !ok {
 				c.Status(http.StatusBadRequest)
 				return
@@ -97,6 +115,37 @@ func AddUIHandlers(r *gin.Engine, processorCh chan<- sharedjob.ProgressMessage)
 			c.Status(http.StatusOK)
 			JobPartUpdates(uiChangedJobs, newJobs, jobID, c.Writer)
 		})
+		ui.GET("/jobs/:jobid/history", func(c *gin.Context) {
+			job, err := sharedjob.FindJob(c.Param("jobid"))
+			if err != nil {
+				c.Status(http.StatusNotFound)
+				return
+			}
+
+			JobPartHistory(&job, c.Writer)
+			c.Status(http.StatusOK)
+		})
+		ui.GET("/chains/:chainid", func(c *gin.Context) {
+			chain, err := sharedjob.GetChain(c.Param("chainid"))
+			if err != nil {
+				c.Status(http.StatusNotFound)
+				return
+			}
+
+			JobPartChain(chain, c.Writer)
+			c.Status(http.StatusOK)
+		})
+		ui.GET("/leaderboard", func(c *gin.Context) {
+			window := sharedjob.LeaderboardWindow(c.DefaultQuery("window", string(sharedjob.LeaderboardSession)))
+			entries, err := sharedjob.GetLeaderboard(window, "wages")
+			if err != nil {
+				c.Status(http.StatusBadRequest)
+				return
+			}
+
+			LeaderboardView("Leaderboard", window, entries, c.Writer)
+			c.Status(http.StatusOK)
+		})
 		ui.GET("/connections", func(c *gin.Context) {
 			ConnectedPlayersView("Players", sharedjob.GetPlayers(), c.Writer)
 			c.Status(http.StatusOK)
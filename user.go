@@ -1,5 +1,11 @@
 package sharedjob
 
-type UserIDPayload struct {
-	Name string `json:"username"`
-}
+type (
+	UserIDPayload struct {
+		Name string `json:"username"`
+	}
+	FinishJobPayload struct {
+		Name string `json:"username"`
+		FinishReport
+	}
+)
@@ -0,0 +1,74 @@
+package sharedjob
+
+import "fmt"
+
+// WageCalculator decides how much a job pays. It is asked whenever a job is created
+// and whenever the car count of a job changes.
+type WageCalculator interface {
+	Wage(jobType JobType, cargo CargoType, carCount int, start, target StationID) int
+}
+
+// FlatWageCalculator pays a fixed amount per car based on the cargo category.
+type FlatWageCalculator struct{}
+
+// DistanceWageCalculator pays hauls by route length. Shunting jobs are paid flat.
+// A haul of ReferenceKm pays 1.5 times the flat wage.
+type DistanceWageCalculator struct {
+	ReferenceKm float64
+}
+
+// SupplyDemandWageCalculator scales the wage of Base with the demand multiplier of the
+// cargo. See demandMultiplier.
+type SupplyDemandWageCalculator struct {
+	Base WageCalculator
+}
+
+var wageCalculator WageCalculator = FlatWageCalculator{}
+
+func NewWageCalculator(model string, referenceKm float64) (WageCalculator, error) {
+	switch model {
+	case "", "flat":
+		return FlatWageCalculator{}, nil
+	case "distance":
+		return DistanceWageCalculator{ReferenceKm: referenceKm}, nil
+	}
+
+	return nil, fmt.Errorf("unknown wage model %s", model)
+}
+
+func calcWage(jobType JobType, cargo CargoType, carCount int, start, target StationID) int {
+	return wageCalculator.Wage(jobType, cargo, carCount, start, target)
+}
+
+func (FlatWageCalculator) Wage(jobType JobType, cargo CargoType, carCount int, start, target StationID) int {
+	if jobType == ShuntingUnloadJobType {
+		return applyWageMultiplier(economy.FreightUnloadWagePerCar) * carCount
+	}
+
+	return cargo.BaseWage() * carCount
+}
+
+func (c DistanceWageCalculator) Wage(jobType JobType, cargo CargoType, carCount int, start, target StationID) int {
+	flat := FlatWageCalculator{}.Wage(jobType, cargo, carCount, start, target)
+	if jobType != FreightJobType && jobType != LogisticHaulJobType {
+		return flat
+	}
+
+	// short hauls still pay half the flat wage so nearby stations stay worth it
+	factor := 0.5 + StationDistance(start, target)/c.ReferenceKm
+	return int(float64(flat) * factor)
+}
+
+func (c SupplyDemandWageCalculator) Wage(jobType JobType, cargo CargoType, carCount int, start, target StationID) int {
+	base := c.Base.Wage(jobType, cargo, carCount, start, target)
+	return int(float64(base) * demandMultiplier(cargo, start, target))
+}
+
+// wageMultiplier returns the demand multiplier if the active wage calculator uses one
+func wageMultiplier(cargo CargoType, start, target StationID) float64 {
+	if _, ok := wageCalculator.(SupplyDemandWageCalculator); !ok {
+		return 1
+	}
+
+	return demandMultiplier(cargo, start, target)
+}
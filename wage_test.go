@@ -0,0 +1,70 @@
+package sharedjob
+
+import "testing"
+
+func TestDistanceWageScalesWithRoute(t *testing.T) {
+	calc := DistanceWageCalculator{ReferenceKm: 5}
+
+	long := calc.Wage(FreightJobType, CrudeOil, 10, StationOWN, StationHB)
+	short := calc.Wage(FreightJobType, CrudeOil, 10, StationFM, StationFF)
+	if long <= short {
+		t.Errorf("expected OWN->HB (%d) to pay more than FM->FF (%d)", long, short)
+	}
+
+	flat := FlatWageCalculator{}
+	if got, want := calc.Wage(ShuntingLoadJobType, Coal, 5, StationCM, StationCM), flat.Wage(ShuntingLoadJobType, Coal, 5, StationCM, StationCM); got != want {
+		t.Errorf("expected shunting jobs to pay flat %d, got %d", want, got)
+	}
+}
+
+func TestSupplyDemandWageDropsWithBacklog(t *testing.T) {
+	ResetWorld()
+	Setup()
+	calc := SupplyDemandWageCalculator{Base: FlatWageCalculator{}}
+
+	starving := calc.Wage(FreightJobType, Coal, 10, StationCM, StationSM)
+	AllStations[StationCM].cargoBuffer[Coal] = 3 * economy.MaxCarsPerJob
+	backlog := calc.Wage(FreightJobType, Coal, 10, StationCM, StationSM)
+	if backlog >= starving {
+		t.Errorf("expected a cargo backlog (%d) to pay less than a starving target (%d)", backlog, starving)
+	}
+}
+
+func TestDemandMultiplierRewardsStarvedInput(t *testing.T) {
+	ResetWorld()
+	Setup()
+
+	for _, proc := range AllStations[StationSM].Processor {
+		proc.buffer[Coal] = economy.MaxCarsPerJob
+	}
+
+	if got := demandMultiplier(IronOre, StationIME, StationSM); got <= 1 {
+		t.Errorf("expected starved iron ore to pay more than 1x, got %.2f", got)
+	}
+	if got := demandMultiplier(Coal, StationCM, StationSM); got >= 1 {
+		t.Errorf("expected piled up coal to pay less than 1x, got %.2f", got)
+	}
+}
+
+func TestDemandMultiplierRatesSingleInputByStock(t *testing.T) {
+	ResetWorld()
+	Setup()
+
+	var refinery *StationProcessor
+	for _, proc := range AllStations[StationHB].Processor {
+		if proc.output == Diesel {
+			refinery = proc
+		}
+	}
+
+	refinery.buffer[CrudeOil] = 0
+	refinery.production = nil
+	if got := demandMultiplier(CrudeOil, StationOWC, StationHB); got <= 1 {
+		t.Errorf("expected an empty single input processor to pay more than 1x, got %.2f", got)
+	}
+
+	refinery.buffer[CrudeOil] = economy.ProductionCapacity + 2*economy.MaxCarsPerJob
+	if got := demandMultiplier(CrudeOil, StationOWC, StationHB); got >= 1 {
+		t.Errorf("expected an overstocked single input processor to pay less than 1x, got %.2f", got)
+	}
+}
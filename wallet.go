@@ -0,0 +1,85 @@
+package sharedjob
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+type (
+	LedgerEntry struct {
+		JobID  string    `json:"job_id,omitempty"`
+		Amount int       `json:"amount"`
+		Time   time.Time `json:"time"`
+		Reason string    `json:"reason"`
+	}
+	Wallet struct {
+		Username string        `json:"username"`
+		Balance  int           `json:"balance"`
+		Ledger   []LedgerEntry `json:"ledger"`
+		Licenses []License     `json:"licenses"`
+	}
+)
+
+var (
+	walletLock = sync.Mutex{}
+	wallets    = map[string]*Wallet{}
+)
+
+// book adds a ledger entry to the users wallet. Negative amounts are payments by the user.
+func book(userName, jobID string, amount int, reason string) {
+	walletLock.Lock()
+	defer walletLock.Unlock()
+
+	bookLocked(walletFor(userName), jobID, amount, reason)
+}
+
+// bookLocked expects walletLock to be held
+func bookLocked(w *Wallet, jobID string, amount int, reason string) {
+	w.Balance += amount
+	w.Ledger = append(w.Ledger, LedgerEntry{
+		JobID:  jobID,
+		Amount: amount,
+		Time:   time.Now(),
+		Reason: reason,
+	})
+}
+
+// GetWallet returns a copy of the users wallet. Unknown users have an empty wallet with
+// the starting licenses.
+func GetWallet(userName string) Wallet {
+	walletLock.Lock()
+	defer walletLock.Unlock()
+
+	w, ok := wallets[userName]
+	if !ok {
+		return newWallet(userName)
+	}
+
+	return Wallet{
+		Username: w.Username,
+		Balance:  w.Balance,
+		Ledger:   slices.Clone(w.Ledger),
+		Licenses: slices.Clone(w.Licenses),
+	}
+}
+
+// walletFor returns the users wallet and creates it if missing. Caller holds walletLock.
+func walletFor(userName string) *Wallet {
+	w, ok := wallets[userName]
+	if !ok {
+		created := newWallet(userName)
+		w = &created
+		wallets[userName] = w
+	}
+
+	return w
+}
+
+func newWallet(userName string) Wallet {
+	return Wallet{
+		Username: userName,
+		Ledger:   []LedgerEntry{},
+		Licenses: slices.Clone(economy.StartingLicenses),
+	}
+}
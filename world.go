@@ -0,0 +1,111 @@
+package sharedjob
+
+import (
+	"math/rand"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	worldSeed int64
+	worldRand = newWorldRand(time.Now().UnixNano())
+)
+
+// lockedSource guards the world RNG. Bots, HTTP handlers and the world clock draw from it
+// concurrently and not every caller holds jobLock. It counts its draws so that a restored
+// world continues the sequence instead of replaying it.
+type lockedSource struct {
+	lock  sync.Mutex
+	src   rand.Source
+	draws uint64
+}
+
+var worldSource *lockedSource
+
+func newWorldRand(seed int64) *rand.Rand {
+	worldSource = &lockedSource{src: rand.NewSource(seed)}
+	return rand.New(worldSource)
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.draws++
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.draws = 0
+	s.src.Seed(seed)
+}
+
+func (s *lockedSource) getDraws() uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.draws
+}
+
+// restoreWorldRand seeds the world RNG and skips the numbers drawn before a save.
+func restoreWorldRand(seed int64, draws uint64) {
+	worldSeed = seed
+	worldRand = newWorldRand(seed)
+	for i := uint64(0); i < draws; i++ {
+		worldRand.Int63()
+	}
+}
+
+// SeedWorld resets the world RNG. Must be called before Setup so that the same seed
+// plus the same actions results in identical job IDs, car counts and tracks.
+// A seed of 0 picks a random seed based on the current time.
+func SeedWorld(seed int64) int64 {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	worldSeed = seed
+	worldRand = newWorldRand(seed)
+
+	return seed
+}
+
+func GetWorldSeed() int64 {
+	return worldSeed
+}
+
+// SortedStationIDs returns all station IDs in a stable order.
+// Use this instead of ranging over AllStations whenever the order has side effects.
+func SortedStationIDs() []StationID {
+	ids := make([]StationID, 0, len(AllStations))
+	for stationID := range AllStations {
+		ids = append(ids, stationID)
+	}
+	slices.Sort(ids)
+
+	return ids
+}
+
+func SortedStations() []*LogicStation {
+	stations := make([]*LogicStation, 0, len(AllStations))
+	for _, stationID := range SortedStationIDs() {
+		stations = append(stations, AllStations[stationID])
+	}
+
+	return stations
+}
+
+// ResetWorld drops all stations, processors and jobs and restarts the world clock. Call
+// SeedWorld and Setup afterwards to build a fresh world, e.g. for repeated in-process runs.
+func ResetWorld() {
+	AllStations = newStationMap()
+	resetArchive()
+	resetLeaderboard()
+	wallets = map[string]*Wallet{}
+	lastCarNum = 0
+	worldTime = time.Now().Round(0)
+}
@@ -0,0 +1,43 @@
+package sharedjob
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSeededWorldIsReproducible(t *testing.T) {
+	snapshot := func() []string {
+		ResetWorld()
+		SeedWorld(42)
+		Setup()
+		GrantLicenses("test", AllLicenses()...)
+
+		// progress the first spawned load job so target selection is exercised too
+		for _, station := range SortedStations() {
+			for _, j := range station.JobQueue {
+				if j.JobType == ShuntingLoadJobType && j.IsSpawned() {
+					progressCh := make(chan ProgressMessage, 100)
+					ReserveJob("test", j.ID)
+					TakeJob("test", j.ID, progressCh)
+					FinishJob("test", j.ID, FinishReport{}, progressCh)
+					break
+				}
+			}
+		}
+
+		lines := make([]string, 0)
+		for _, station := range SortedStations() {
+			for _, j := range station.JobQueue {
+				lines = append(lines, fmt.Sprintf("%s %s %s %d %v", j.ID, j.StartingTrack, j.TargetTrack, j.CarCount, j.Cars))
+			}
+		}
+
+		return lines
+	}
+
+	first := snapshot()
+	second := snapshot()
+	if fmt.Sprint(first) != fmt.Sprint(second) {
+		t.Errorf("same seed produced different worlds:\n%v\n%v", first, second)
+	}
+}